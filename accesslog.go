@@ -0,0 +1,258 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CombinedLogTimeFormat is the time layout used by the Apache Combined Log
+// Format.
+const CombinedLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog is middleware that logs every request, e.g.
+//
+//	accessLog := &httprouter.AccessLog{Logger: slog.Default()}
+//	router.Use(accessLog.Middleware)
+//
+// Each record holds the method, path, route pattern, path parameters, status,
+// size, latency, remote address and request ID.
+//
+// The route pattern and parameters are only known when the middleware is
+// installed via Router.Use.
+type AccessLog struct {
+	// Logger receives a record for each request. If it is nil,
+	// slog.Default() is used.
+	Logger *slog.Logger
+
+	// Level is the level of the log records. The default is slog.LevelInfo.
+	Level slog.Level
+
+	// Combined, if not nil, receives a line in Apache Combined Log Format for
+	// each request, instead of the records being sent to Logger.
+	Combined io.Writer
+
+	// TrustedProxies lists the networks of proxies whose X-Forwarded-For
+	// headers are believed. The remote address that is logged is the nearest
+	// address that is not a trusted proxy. If there are no trusted proxies,
+	// X-Forwarded-For is ignored.
+	TrustedProxies []netip.Prefix
+
+	// RequestIDHeader is the header holding the request ID, which is used if
+	// the RequestID middleware has not stored one in the request context, as
+	// happens when it comes after this. The ID is then taken from the
+	// response header, where RequestID echoes it, or else from the request
+	// header. The default is "X-Request-ID".
+	RequestIDHeader string
+
+	// SampleEvery, if greater than one, causes only one in every so many
+	// requests to be logged. Server errors (status 5xx) are always logged.
+	SampleEvery int
+
+	// Exclude lists request paths that are not logged, e.g. health checks.
+	// A path that ends with "*" excludes all the paths that it prefixes.
+	Exclude []string
+
+	count atomic.Uint64
+	mu    sync.Mutex // serialises writes to Combined
+}
+
+// Middleware wraps the next handler so that its requests are logged. It is
+// a Middleware suitable for Router.Use.
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if a.excluded(req.URL.Path) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		start := time.Now()
+		sw := NewStatusWriter(w)
+		req, route := CaptureRoute(req)
+		received := *req.URL // as received, because SubRouter and redirects alter it
+
+		completed := false
+		defer func() {
+			status := sw.Status()
+			if status == 0 {
+				status = http.StatusOK
+				if !completed {
+					status = http.StatusInternalServerError // the handler panicked
+				}
+			}
+
+			if a.sampled(status) {
+				a.log(req, &received, route, sw.Header(), status, sw.Size(), start)
+			}
+		}()
+
+		next.ServeHTTP(sw, req)
+		completed = true
+	})
+}
+
+func (a *AccessLog) excluded(path string) bool {
+	for _, x := range a.Exclude {
+		if prefix, ok := strings.CutSuffix(x, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == x {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AccessLog) sampled(status int) bool {
+	if a.SampleEvery <= 1 || status >= 500 {
+		return true
+	}
+	return a.count.Add(1)%uint64(a.SampleEvery) == 1
+}
+
+func (a *AccessLog) log(req *http.Request, u *url.URL, route *RouteInfo, header http.Header, status int, size int64, start time.Time) {
+	if a.Combined != nil {
+		a.writeCombined(req, u, status, size, start)
+		return
+	}
+
+	logger := a.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	attrs := make([]slog.Attr, 0, 9)
+	attrs = append(attrs,
+		slog.String("method", req.Method),
+		slog.String("path", u.Path),
+		slog.String("route", route.Pattern),
+	)
+
+	if len(route.Params) > 0 {
+		params := make([]any, len(route.Params))
+		for i, p := range route.Params {
+			params[i] = slog.String(p.Key, p.Value)
+		}
+		attrs = append(attrs, slog.Group("params", params...))
+	}
+
+	attrs = append(attrs,
+		slog.Int("status", status),
+		slog.Int64("size", size),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("remote_addr", a.remoteAddr(req)),
+	)
+
+	if id := a.requestID(req, header); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	logger.LogAttrs(context.Background(), a.Level, "request", attrs...)
+}
+
+// requestID gets the ID set by the RequestID middleware, falling back to
+// the response header and then the request header.
+func (a *AccessLog) requestID(req *http.Request, header http.Header) string {
+	if id := RequestIDFromContext(req.Context()); id != "" {
+		return id
+	}
+
+	name := a.RequestIDHeader
+	if name == "" {
+		name = "X-Request-ID"
+	}
+	if id := header.Get(name); id != "" {
+		return id
+	}
+	return req.Header.Get(name)
+}
+
+// remoteAddr gets the client address (see ClientIP).
+func (a *AccessLog) remoteAddr(req *http.Request) string {
+	return ClientIP(req, a.TrustedProxies)
+}
+
+// writeCombined writes a line in Apache Combined Log Format, i.e.
+//
+//	%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i"
+func (a *AccessLog) writeCombined(req *http.Request, u *url.URL, status int, size int64, start time.Time) {
+	user := "-"
+	if u.User != nil && u.User.Username() != "" {
+		user = u.User.Username()
+	} else if name, _, ok := req.BasicAuth(); ok && name != "" {
+		user = name
+	}
+
+	uri := req.RequestURI
+	if uri == "" {
+		uri = u.RequestURI()
+	}
+
+	b := make([]byte, 0, 256)
+	b = append(b, a.remoteAddr(req)...)
+	b = append(b, " - "...)
+	b = appendEscaped(b, user)
+	b = append(b, " ["...)
+	b = start.AppendFormat(b, CombinedLogTimeFormat)
+	b = append(b, "] \""...)
+	b = appendEscaped(b, req.Method)
+	b = append(b, ' ')
+	b = appendEscaped(b, uri)
+	b = append(b, ' ')
+	b = appendEscaped(b, req.Proto)
+	b = append(b, "\" "...)
+	b = strconv.AppendInt(b, int64(status), 10)
+	b = append(b, ' ')
+	if size > 0 {
+		b = strconv.AppendInt(b, size, 10)
+	} else {
+		b = append(b, '-')
+	}
+	b = append(b, " \""...)
+	b = appendEscaped(b, headerOrDash(req, "Referer"))
+	b = append(b, "\" \""...)
+	b = appendEscaped(b, headerOrDash(req, "User-Agent"))
+	b = append(b, "\"\n"...)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Combined.Write(b)
+}
+
+func headerOrDash(req *http.Request, name string) string {
+	if v := req.Header.Get(name); v != "" {
+		return v
+	}
+	return "-"
+}
+
+// appendEscaped appends s with quotes, backslashes and control characters
+// escaped in the way that Apache does.
+func appendEscaped(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c < 0x20 || c == 0x7f:
+			b = append(b, '\\', 'x', hex[c>>4], hex[c&0xf])
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/onsi/gomega"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLog_structured(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+	router.GET("/users/:id", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+	g.Expect(record).To(HaveKeyWithValue("msg", "request"))
+	g.Expect(record).To(HaveKeyWithValue("method", "GET"))
+	g.Expect(record).To(HaveKeyWithValue("path", "/users/42"))
+	g.Expect(record).To(HaveKeyWithValue("route", "/users/:id"))
+	g.Expect(record).To(HaveKeyWithValue("params", map[string]any{"id": "42"}))
+	g.Expect(record).To(HaveKeyWithValue("status", 200.0))
+	g.Expect(record).To(HaveKeyWithValue("size", 5.0))
+	g.Expect(record).To(HaveKey("latency"))
+	g.Expect(record).To(HaveKeyWithValue("remote_addr", "192.0.2.1"))
+	g.Expect(record).To(HaveKeyWithValue("request_id", "abc"))
+}
+
+func TestAccessLog_not_found(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	var record map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+	g.Expect(record).To(HaveKeyWithValue("route", ""))
+	g.Expect(record).NotTo(HaveKey("params"))
+	g.Expect(record).To(HaveKeyWithValue("status", 404.0))
+}
+
+func TestAccessLog_panic(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+	router.GET("/boom", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		panic("boom")
+	})
+
+	g.Expect(func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	}).To(PanicWith("boom"))
+
+	var record map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+	g.Expect(record).To(HaveKeyWithValue("status", 500.0))
+}
+
+func TestAccessLog_combined(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Combined: buf}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+	router.GET("/a", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/a?x=1", nil)
+	req.SetBasicAuth("frank", "secret")
+	req.Header.Set("Referer", "http://example.com/start")
+	req.Header.Set("User-Agent", `Mozilla/4.08 "quoted"`)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/b", nil))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	g.Expect(lines).To(HaveLen(2))
+	g.Expect(lines[0]).To(MatchRegexp(`^192\.0\.2\.1 - - \[\d\d/\w{3}/\d{4}:\d\d:\d\d:\d\d [-+]\d{4}] "GET /b HTTP/1\.1" 404 19 "-" "-"$`))
+	g.Expect(lines[1]).To(MatchRegexp(`^192\.0\.2\.1 - frank \[.+] "GET /a\?x=1 HTTP/1\.1" 200 5 "http://example\.com/start" "Mozilla/4\.08 \\"quoted\\""$`))
+}
+
+func TestAccessLog_path_as_received(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+	router.SubRouter("/sub/*", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	router.GET("/x/", func(w http.ResponseWriter, req *http.Request, _ Params) {})
+
+	for _, path := range []string{"/sub/abc", "/x"} {
+		buf.Reset()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+		var record map[string]any
+		g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+		g.Expect(record).To(HaveKeyWithValue("path", path))
+	}
+}
+
+func TestAccessLog_exclusions_and_sampling(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{
+		Combined:    buf,
+		Exclude:     []string{"/healthz", "/static/*"},
+		SampleEvery: 3,
+	}
+
+	router := New()
+	router.Use(accessLog.Middleware)
+	router.GET("/ok", func(w http.ResponseWriter, req *http.Request, _ Params) {})
+	router.GET("/fail", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	for _, path := range []string{"/healthz", "/static/a.css", "/ok", "/ok", "/ok", "/ok", "/fail", "/fail"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	g.Expect(lines).To(HaveLen(4))
+	g.Expect(lines[0]).To(ContainSubstring(`"GET /ok HTTP/1.1" 200`))
+	g.Expect(lines[1]).To(ContainSubstring(`"GET /ok HTTP/1.1" 200`))
+	g.Expect(lines[2]).To(ContainSubstring(`"GET /fail HTTP/1.1" 502`))
+	g.Expect(lines[3]).To(ContainSubstring(`"GET /fail HTTP/1.1" 502`))
+}
@@ -8,7 +8,8 @@ import (
 // AllMethods is a list of all the 'normal' HTTP methods,
 // i.e. HEAD, GET, PUT, POST, DELETE, PATCH, OPTIONS.
 //
-// It does not include CONNECT or TRACE by default.
+// It does not include CONNECT or TRACE by default; see Router.CONNECT and
+// Router.HandleTRACE for opt-in support for these.
 // It doesn't include methods used by extension protocols such as WebDav.
 // However, you can change it if you need a different set of methods.
 var AllMethods = []string{
@@ -71,21 +72,12 @@ func (r *Router) SubRouter(path string, handler http.Handler, methods ...string)
 		methods = AllMethods
 	}
 
-	r.HandleAll(path, func(w http.ResponseWriter, req *http.Request, ps Params) {
-		req.URL.Path = ps.ByName("filepath")
-		handler.ServeHTTP(w, storeParams(ps, req))
-	}, methods...)
-}
-
-func storeParams(p Params, req *http.Request) *http.Request {
-	if len(p) > 0 {
-		req = req.WithContext(WithParams(req.Context(), p))
-	}
-	return req
-}
-
-func adapter(handler http.Handler) func(w http.ResponseWriter, req *http.Request, p Params) {
-	return func(w http.ResponseWriter, req *http.Request, p Params) {
-		handler.ServeHTTP(w, storeParams(p, req))
+	for _, m := range methods {
+		var rt *Route
+		rt = r.Handle(m, path, func(w http.ResponseWriter, req *http.Request, ps Params) {
+			req.URL.Path = ps.ByName("filepath")
+			handler.ServeHTTP(w, rt.withContext(req, ps))
+		})
+		rt.contextual = true
 	}
 }
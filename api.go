@@ -6,6 +6,7 @@ package httprouter
 
 import (
 	"net/http"
+	"sort"
 	"sync"
 )
 
@@ -17,17 +18,63 @@ type Handle func(http.ResponseWriter, *http.Request, Params)
 // Router is a http.Handler which can be used to dispatch requests to different
 // handler functions via configurable routes
 type Router struct {
-	trees map[string]*node
+	// A single tree holds every route; each leaf holds the handles for
+	// all the methods registered for its path.
+	tree *node
+
+	// The methods for which at least one route has been registered
+	methods []string
+
+	// CONNECT routes, matched by authority instead of by path
+	connect []connectRoute
+
+	// The registered routes, grouped by method and path
+	routes map[routeKey][]*Route
+
+	// The routers mounted by Mount, including those mounted within them
+	mounts []mount
+
+	// The handlers tried before NotFound, added by Fallback
+	fallbacks []http.Handler
 
 	paramsPool sync.Pool
 	maxParams  uint16
 
+	// The middleware added by Use, and the handler chain built from it
+	middleware []Middleware
+	chain      http.Handler
+
+	// Set by Recovery.Install, so that the PanicHandler knows whether the
+	// response has been committed
+	tracksCommit bool
+
 	// If enabled, adds the matched route path onto the http.Request context
 	// before invoking the handler.
 	// The matched route path is only added to handlers of routes that were
 	// registered when this option was enabled.
+	//
+	// Deprecated: use RouteFromContext instead. For routes registered with a
+	// Handle function, it needs Router.SaveRouteInfo to be enabled, which
+	// applies to all routes, whenever they were registered.
 	SaveMatchedRoutePath bool
 
+	// If enabled, adds a RouteInfo describing the matched route onto the
+	// http.Request context before invoking the handler of routes registered
+	// with a Handle function. It can be obtained using RouteFromContext.
+	// This is not needed for routes registered with a http.Handler (e.g. via
+	// Handler, SubRouter or ServeFiles), which always have the RouteInfo.
+	// This option applies to all routes, regardless of when they were
+	// registered. It is disabled by default because it costs an extra
+	// allocation per request.
+	SaveRouteInfo bool
+
+	// An optional function that is called after a route has been matched,
+	// just before its handler is invoked. It receives a description of the
+	// matched route. This is an integration point for tracing middleware,
+	// which can use it to name spans by route pattern rather than by request
+	// path, e.g. "GET /users/:id".
+	OnMatch func(req *http.Request, info *RouteInfo)
+
 	// Enables automatic redirection if the current route can't be matched but a
 	// handler for the path with (without) the trailing slash exists.
 	// For example if /foo/ is requested but a route only exists for /foo, the
@@ -54,6 +101,27 @@ type Router struct {
 	// handler.
 	HandleMethodNotAllowed bool
 
+	// If enabled, HEAD requests that are served by GET handles (because no
+	// HEAD handle was registered) use a response writer that discards the
+	// body, counting its length so that the Content-Length header is set.
+	// Handlers can use IsHead to skip generating the body altogether.
+	HeadContentLength bool
+
+	// If enabled, the router automatically replies to TRACE requests for
+	// which no TRACE handle has been registered, by echoing the request
+	// (without any headers listed in TraceStrippedHeaders).
+	// This is disabled by default because TRACE can leak information
+	// to cross-site scripts.
+	HandleTRACE bool
+
+	// If not empty, POST requests may have their method overridden by the
+	// X-HTTP-Method-Override header or by the _method field of a small
+	// urlencoded form (see MethodOverrideField), provided that the new
+	// method is listed here, e.g. PUT, PATCH, DELETE. This is
+	// for clients, such as HTML forms, that can only send GET and POST.
+	// The override happens before the route is looked up.
+	MethodOverrides []string
+
 	// If enabled, the router automatically replies to OPTIONS requests.
 	// Custom OPTIONS handlers take priority over automatic replies.
 	HandleOPTIONS bool
@@ -68,10 +136,16 @@ type Router struct {
 	globalAllowed string
 
 	// Configurable http.Handler which is called when no matching route is
-	// found. Also use this if you need to cascade to another router (perhaps
-	// via intermediate middleware). If it is not set, http.NotFound is used.
+	// found. If it is not set, http.NotFound is used. To cascade to other
+	// routers or handlers instead, use Fallback.
 	NotFound http.Handler
 
+	// Configurable http.Handler which is called when none of the routes for
+	// the method and path produces a media type that is acceptable to the
+	// client, according to its Accept header (see Route.Produces).
+	// If it is not set, http.Error with http.StatusNotAcceptable is used.
+	NotAcceptable http.Handler
+
 	// Configurable http.Handler which is called when a request
 	// cannot be routed and HandleMethodNotAllowed is true.
 	// If it is not set, http.Error with http.StatusMethodNotAllowed is used.
@@ -79,6 +153,40 @@ type Router struct {
 	// is called.
 	MethodNotAllowed http.Handler
 
+	// Configurable http.Handler which is called when a request exceeds the
+	// route's rate limit (see Route.WithRateLimit). If it is not set,
+	// http.Error with http.StatusTooManyRequests is used. The Retry-After
+	// and RateLimit headers are set before the handler is called.
+	TooManyRequests http.Handler
+
+	// Configurable http.Handler which is called when a request is shed
+	// because the route's concurrency limit is exceeded (see
+	// Route.WithConcurrencyLimit). If it is not set, http.Error with
+	// http.StatusServiceUnavailable is used. The Retry-After header is set
+	// before the handler is called.
+	Overloaded http.Handler
+
+	// Configurable http.Handler which is called when a request body is
+	// larger than the route allows (see Route.WithMaxBodySize). If it is not
+	// set, http.Error with http.StatusRequestEntityTooLarge is used.
+	RequestTooLarge http.Handler
+
+	// The default maximum size, in bytes, of request bodies. This applies to
+	// routes registered after it is set, unless they are registered via a
+	// Group with its own limit, or are given their own limit using
+	// Route.WithMaxBodySize. Zero means that there is no limit.
+	MaxBodySize int64
+
+	// Configurable http.Handler which is called when a route's timeout
+	// expires before its handle has started the response (see
+	// Route.WithTimeout). If it is not set, http.Error with
+	// http.StatusServiceUnavailable is used.
+	OnTimeout http.Handler
+
+	// An optional Observer that is notified about every request, e.g. for
+	// gathering metrics labelled by route pattern.
+	Observer Observer
+
 	// PanicHandler is a function to handle panics recovered from http handlers. It should
 	// be used to generate an error page and return the http error code 500 (Internal
 	// Server Error).
@@ -87,6 +195,12 @@ type Router struct {
 	// unrecovered panics. If a panic occurs and this handler is defined, the
 	// built-in recover() function obtains the cause and it is passed to the
 	// third parameter of this function.
+	//
+	// Panics with http.ErrAbortHandler are not passed to this handler; they
+	// are re-panicked so that net/http can abort the response.
+	//
+	// Recovery provides a ready-made handler that logs the stack trace; see
+	// Recovery.Install.
 	PanicHandler func(http.ResponseWriter, *http.Request, interface{})
 }
 
@@ -116,24 +230,27 @@ func (r *Router) putParams(ps *Params) {
 	}
 }
 
-func (r *Router) saveMatchedRoutePath(path string, handle Handle) Handle {
+// saveMatchedRoutePath wraps the route's handle so that the route's path is
+// added to the Params. The route is consulted when serving, because its path
+// and router change if it is mounted (see Mount).
+func saveMatchedRoutePath(rt *Route, handle Handle) Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps Params) {
 		if ps == nil {
-			psp := r.getParams()
+			psp := rt.router.getParams()
 			ps = (*psp)[0:1]
-			ps[0] = Param{Key: MatchedRoutePathParam, Value: path}
+			ps[0] = Param{Key: MatchedRoutePathParam, Value: rt.path}
 			handle(w, req, ps)
-			r.putParams(psp)
+			rt.router.putParams(psp)
 		} else {
-			ps = append(ps, Param{Key: MatchedRoutePathParam, Value: path})
+			ps = append(ps, Param{Key: MatchedRoutePathParam, Value: rt.path})
 			handle(w, req, ps)
 		}
 	}
 }
 
 // GET is a shortcut for router.Handle(http.MethodGet, path, handle)
-func (r *Router) GET(path string, handle Handle) {
-	r.Handle(http.MethodGet, path, handle)
+func (r *Router) GET(path string, handle Handle) *Route {
+	return r.Handle(http.MethodGet, path, handle)
 }
 
 // HEAD is a shortcut for router.Handle(http.MethodHead, path, handle). Note
@@ -141,33 +258,33 @@ func (r *Router) GET(path string, handle Handle) {
 // default handler for equivalent HEAD requests. So it is only necessary to
 // register HEAD handlers if they are different from or in addition to the
 // GET handlers.
-func (r *Router) HEAD(path string, handle Handle) {
-	r.Handle(http.MethodHead, path, handle)
+func (r *Router) HEAD(path string, handle Handle) *Route {
+	return r.Handle(http.MethodHead, path, handle)
 }
 
 // OPTIONS is a shortcut for router.Handle(http.MethodOptions, path, handle)
-func (r *Router) OPTIONS(path string, handle Handle) {
-	r.Handle(http.MethodOptions, path, handle)
+func (r *Router) OPTIONS(path string, handle Handle) *Route {
+	return r.Handle(http.MethodOptions, path, handle)
 }
 
 // POST is a shortcut for router.Handle(http.MethodPost, path, handle)
-func (r *Router) POST(path string, handle Handle) {
-	r.Handle(http.MethodPost, path, handle)
+func (r *Router) POST(path string, handle Handle) *Route {
+	return r.Handle(http.MethodPost, path, handle)
 }
 
 // PUT is a shortcut for router.Handle(http.MethodPut, path, handle)
-func (r *Router) PUT(path string, handle Handle) {
-	r.Handle(http.MethodPut, path, handle)
+func (r *Router) PUT(path string, handle Handle) *Route {
+	return r.Handle(http.MethodPut, path, handle)
 }
 
 // PATCH is a shortcut for router.Handle(http.MethodPatch, path, handle)
-func (r *Router) PATCH(path string, handle Handle) {
-	r.Handle(http.MethodPatch, path, handle)
+func (r *Router) PATCH(path string, handle Handle) *Route {
+	return r.Handle(http.MethodPatch, path, handle)
 }
 
 // DELETE is a shortcut for router.Handle(http.MethodDelete, path, handle)
-func (r *Router) DELETE(path string, handle Handle) {
-	r.Handle(http.MethodDelete, path, handle)
+func (r *Router) DELETE(path string, handle Handle) *Route {
+	return r.Handle(http.MethodDelete, path, handle)
 }
 
 // Handle registers a new request handle with the given path and method.
@@ -178,7 +295,19 @@ func (r *Router) DELETE(path string, handle Handle) {
 // This function is intended for bulk loading and to allow the usage of less
 // frequently used, non-standardized or custom methods (e.g. for internal
 // communication with a proxy).
-func (r *Router) Handle(method, path string, handle Handle) {
+//
+// The returned Route can be refined further, e.g. using Route.Produces or
+// Route.Match.
+// More than one route can be registered for the same method and path only
+// if the earlier routes have been so refined.
+//
+// All methods share the same tree of paths, so the usual rules about
+// conflicting wildcards apply across methods as well as within each method,
+// except that routes for different methods may give the same wildcard
+// different names, e.g. GET /users/:id and POST /users/:name.
+// If the route conflicts with an existing route, Handle panics with a
+// *ConflictError; use TryHandle or Validate to get an error instead.
+func (r *Router) Handle(method, path string, handle Handle) *Route {
 	varsCount := uint16(0)
 
 	if method == "" {
@@ -191,25 +320,42 @@ func (r *Router) Handle(method, path string, handle Handle) {
 		panic("handle must not be nil")
 	}
 
+	rt := newRoute(r, method, path, handle)
 	if r.SaveMatchedRoutePath {
 		varsCount++
-		handle = r.saveMatchedRoutePath(path, handle)
+		rt.handle = saveMatchedRoutePath(rt, handle)
 	}
 
-	if r.trees == nil {
-		r.trees = make(map[string]*node)
+	r.add(rt, varsCount)
+	return rt
+}
+
+// add puts a route into the tree. The route may have been created by this
+// router or adopted from a mounted router.
+func (r *Router) add(rt *Route, varsCount uint16) {
+	method, path := rt.method, rt.path
+
+	if r.tree == nil {
+		r.tree = new(node)
+		r.routes = make(map[routeKey][]*Route)
 	}
 
-	root := r.trees[method]
-	if root == nil {
-		root = new(node)
-		r.trees[method] = root
+	if key := (routeKey{method: method, path: path}); len(r.routes[key]) > 0 {
+		r.addVariant(rt)
+	} else {
+		if msg := checkWildcards(path); msg != "" {
+			panic(msg)
+		}
+		r.tree.addRoute(method, path, rt.handle)
+		r.routes[key] = []*Route{rt}
+		r.refresh(key)
+	}
 
+	if !contains(r.methods, method) {
+		r.methods = append(r.methods, method)
 		r.globalAllowed = r.allowed("*", "")
 	}
 
-	root.addRoute(path, handle)
-
 	// Update maxParams
 	if paramsCount := countParams(path); paramsCount+varsCount > r.maxParams {
 		r.maxParams = paramsCount + varsCount
@@ -226,24 +372,30 @@ func (r *Router) Handle(method, path string, handle Handle) {
 
 // Handler is an adapter which allows the usage of an http.Handler as a
 // request handle.
-// The Params are available in the request context under ParamsKey.
-func (r *Router) Handler(method, path string, handler http.Handler) {
-	r.Handle(method, path, adapter(handler))
+// The Params are available in the request context under ParamsKey, and the
+// RouteInfo is available via RouteFromContext.
+func (r *Router) Handler(method, path string, handler http.Handler) *Route {
+	var rt *Route
+	rt = r.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps Params) {
+		handler.ServeHTTP(w, rt.withContext(req, ps))
+	})
+	rt.contextual = true
+	return rt
 }
 
 // HandlerFunc is an adapter which allows the use of an http.HandlerFunc as a
 // request handle.
-func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
-	r.Handler(method, path, handler)
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) *Route {
+	return r.Handler(method, path, handler)
 }
 
-// ServeFiles serves files from the given file system root using the http.FileServer
-// handler. Note that http.NotFound is used instead of the Router's NotFound handler;
-// if this is inconvenient, consider using SubRouter with your own file server instead
-// (see https://github.com/rickb777/servefiles/v3 for example).
+// ServeFiles serves files from the given file system root. It is like ServeFS
+// but for a http.FileSystem, and it returns StaticFiles that can be configured
+// in the same ways, e.g. to disable directory listings. Requests for files
+// that do not exist are answered using the router's NotFound handler.
 //
-// The path must end with "/*filepath" (or simply "/*" is allowed in this case), files
-// are then served from the local path /defined/root/dir/*filepath.
+// The path must end with "/*filepath", files are then served from the local
+// path /defined/root/dir/*filepath.
 //
 // For example if root is "/etc" and *filepath is "passwd", the local file
 // "/etc/passwd" would be served.
@@ -259,18 +411,13 @@ func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
 // This allows, for example, use of the asset handler
 // github.com/rickb777/servefiles/v3 with its improved HTTP header
 // configuration.
-func (r *Router) ServeFiles(path string, root http.FileSystem) {
+func (r *Router) ServeFiles(path string, root http.FileSystem) *StaticFiles {
 	if len(path) < 10 || path[len(path)-10:] != "/*filepath" {
 		panic("path must end with /*filepath in path '" + path + "'")
 	}
 
-	fileServer := http.FileServer(root)
-
-	r.GET(path, func(w http.ResponseWriter, req *http.Request, ps Params) {
-		req.URL.Path = ps.ByName("filepath")
-		fileServer.ServeHTTP(w, req)
-	})
 	// Note that HEAD requests are handled automatically
+	return r.ServeFS(path, httpFS{root})
 }
 
 // Lookup allows the manual lookup of a method + path combo.
@@ -280,16 +427,20 @@ func (r *Router) ServeFiles(path string, root http.FileSystem) {
 // values. Otherwise the third return value indicates whether a redirection to
 // the same path with an extra / without the trailing slash should be performed.
 func (r *Router) Lookup(method, path string) (Handle, Params, bool) {
-	if root := r.trees[method]; root != nil {
-		handle, ps, tsr := root.getValue(path, r.getParams)
-		if handle == nil {
+	if r.tree != nil {
+		handles, ps, tsr := r.tree.getValue(method, path, r.getParams)
+		m := handles.find(method)
+		if m == nil {
 			r.putParams(ps)
 			return nil, nil, tsr
 		}
 		if ps == nil {
-			return handle, nil, tsr
+			return m.handle, nil, tsr
 		}
-		return handle, *ps, tsr
+		if m.keys != nil {
+			m.rename(ps)
+		}
+		return m.handle, *ps, tsr
 	}
 	return nil, nil, false
 }
@@ -302,14 +453,11 @@ func (r *Router) Lookup(method, path string) (Handle, Params, bool) {
 // This is intended for debugging and diagnostics.
 func (r *Router) ListPaths(method string) map[string][]string {
 	result := make(map[string][]string)
-	if method == "" {
-		for m, root := range r.trees {
-			result[m] = root.makePathList(nil, nil)
-		}
-	} else {
-		if root := r.trees[method]; root != nil {
-			result[method] = root.makePathList(nil, nil)
-		}
+	if r.tree != nil {
+		r.tree.makePathList(method, nil, result)
+	}
+	for _, list := range result {
+		sort.Strings(list)
 	}
 	return result
 }
@@ -0,0 +1,86 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// WithMaxBodySize limits the size, in bytes, of the route's request bodies,
+// overriding Router.MaxBodySize and any limit set by a Group.
+//
+// Requests that declare a larger Content-Length are answered using
+// Router.RequestTooLarge without calling the handle. Otherwise the body is
+// wrapped using http.MaxBytesReader, so reading beyond the limit fails with a
+// *http.MaxBytesError. If the handle then returns without having started the
+// response, the router answers using Router.TooLarge.
+//
+// Zero removes the limit.
+func (rt *Route) WithMaxBodySize(n int64) *Route {
+	return rt.refine(func() { rt.maxBodySize = n })
+}
+
+// MaxBodySize gets the maximum request body size of the route, or zero if
+// there is no limit.
+func (rt *Route) MaxBodySize() int64 {
+	return rt.maxBodySize
+}
+
+// TooLarge answers a request whose body is too large, using RequestTooLarge
+// if it is set. Handles can use this when reading the body fails with a
+// *http.MaxBytesError.
+func (r *Router) TooLarge(w http.ResponseWriter, req *http.Request) {
+	if r.RequestTooLarge != nil {
+		r.RequestTooLarge.ServeHTTP(w, req)
+	} else {
+		http.Error(w,
+			http.StatusText(http.StatusRequestEntityTooLarge),
+			http.StatusRequestEntityTooLarge,
+		)
+	}
+}
+
+// withMaxBodySize wraps the handle so that request bodies are limited.
+func (r *Router) withMaxBodySize(handle Handle, n int64) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		if req.ContentLength > n {
+			r.TooLarge(w, req)
+			return
+		}
+		if req.Body == nil || req.Body == http.NoBody {
+			handle(w, req, ps)
+			return
+		}
+
+		// a copy of the request is limited, leaving the caller's unaltered
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(w, req.Body, n)}
+		limited := *req
+		limited.Body = body
+		sw := NewStatusWriter(w)
+		handle(sw, &limited, ps)
+		if body.exceeded.Load() && !sw.Committed() {
+			r.TooLarge(sw, req)
+		}
+	}
+}
+
+// limitedBody notes whether reading the body failed because it was too
+// large. The handle may read it in another goroutine (see Route.WithTimeout).
+type limitedBody struct {
+	io.ReadCloser
+	exceeded atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if err != nil && errors.As(err, &tooLarge) {
+		b.exceeded.Store(true)
+	}
+	return n, err
+}
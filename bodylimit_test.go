@@ -0,0 +1,149 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	. "github.com/onsi/gomega"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readBody(router *Router) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		b, err := io.ReadAll(req.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			router.TooLarge(w, req)
+			return
+		}
+		w.Write(b)
+	}
+}
+
+func TestRoute_WithMaxBodySize(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.POST("/small", readBody(router)).WithMaxBodySize(5)
+
+	cases := []struct {
+		body          string
+		contentLength int64
+		code          int
+	}{
+		{body: "hello", contentLength: 5, code: http.StatusOK},
+		{body: "hello!", contentLength: 6, code: http.StatusRequestEntityTooLarge},
+		{body: "hello!", contentLength: -1, code: http.StatusRequestEntityTooLarge}, // chunked
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/small", strings.NewReader(c.body))
+		req.ContentLength = c.contentLength
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		g.Expect(w.Code).To(Equal(c.code), c.body)
+	}
+}
+
+func TestRoute_WithMaxBodySize_RequestTooLarge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	called := false
+	router := New()
+	router.RequestTooLarge = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.POST("/small", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		called = true
+	}).WithMaxBodySize(1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/small", strings.NewReader("ab")))
+
+	g.Expect(w.Code).To(Equal(http.StatusTeapot))
+	g.Expect(called).To(BeFalse())
+}
+
+func TestRoute_WithMaxBodySize_unanswered(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.POST("/ignores", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		io.ReadAll(req.Body)
+	}).WithMaxBodySize(5)
+	router.POST("/answers", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		if _, err := io.ReadAll(req.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}).WithMaxBodySize(5)
+	router.POST("/timed", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		io.ReadAll(req.Body)
+	}).WithMaxBodySize(5).WithTimeout(time.Second)
+
+	cases := []struct {
+		path, body string
+		code       int
+	}{
+		{path: "/ignores", body: "hello", code: http.StatusOK},
+		{path: "/ignores", body: "hello!", code: http.StatusRequestEntityTooLarge},
+		{path: "/answers", body: "hello!", code: http.StatusBadRequest},
+		{path: "/timed", body: "hello!", code: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+		req.ContentLength = -1 // chunked
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		g.Expect(w.Code).To(Equal(c.code), c.path+" "+c.body)
+	}
+}
+
+func TestRoute_WithMaxBodySize_requestUnaltered(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var limited io.ReadCloser
+	router := New()
+	router.POST("/small", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		limited = req.Body
+	}).WithMaxBodySize(5)
+
+	body := io.NopCloser(strings.NewReader("hello"))
+	req := httptest.NewRequest(http.MethodPost, "/small", nil)
+	req.Body = body
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	g.Expect(limited).NotTo(BeIdenticalTo(body))
+	g.Expect(req.Body).To(BeIdenticalTo(body))
+}
+
+func TestRouter_MaxBodySize_defaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.POST("/before", noop)
+	router.MaxBodySize = 1 << 20
+	router.POST("/json", noop)
+	uploads := router.Group("/uploads").WithMaxBodySize(100 << 20)
+	uploads.POST("/images", noop)
+	uploads.POST("/any", noop).WithMaxBodySize(0)
+
+	sizes := make(map[string]int64)
+	for _, rt := range router.Routes() {
+		sizes[rt.Path()] = rt.MaxBodySize()
+	}
+
+	g.Expect(sizes).To(Equal(map[string]int64{
+		"/before":         0,
+		"/json":           1 << 20,
+		"/uploads/images": 100 << 20,
+		"/uploads/any":    0,
+	}))
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP gets the client address of a request. This is the remote address
+// of the connection unless that is a trusted proxy, in which case
+// X-Forwarded-For is searched from right to left for the nearest address that
+// is not a trusted proxy. If there are no trusted proxies, X-Forwarded-For is
+// ignored, because it is easily forged.
+func ClientIP(req *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if len(trustedProxies) == 0 || !trusted(host, trustedProxies) {
+		return host
+	}
+
+	forwarded := req.Header.Values("X-Forwarded-For")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hops := strings.Split(forwarded[i], ",")
+		for j := len(hops) - 1; j >= 0; j-- {
+			hop := strings.TrimSpace(hops[j])
+			if hop == "" {
+				continue
+			}
+			host = hop
+			if !trusted(hop, trustedProxies) {
+				return hop
+			}
+		}
+	}
+
+	return host // every hop was trusted, so the furthest is the client
+}
+
+func trusted(host string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	trustedProxies := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.0/24"),
+	}
+
+	cases := []struct {
+		remote string
+		xff    []string
+		expect string
+	}{
+		{remote: "198.51.100.7:1234", xff: []string{"203.0.113.9"}, expect: "198.51.100.7"},
+		{remote: "pipe", expect: "pipe"},
+		{remote: "192.0.2.1:1234", expect: "192.0.2.1"},
+		{remote: "192.0.2.1:1234", xff: []string{"203.0.113.9, 10.1.2.3"}, expect: "203.0.113.9"},
+		{remote: "192.0.2.1:1234", xff: []string{"1.1.1.1, 203.0.113.9", "10.1.2.3"}, expect: "203.0.113.9"},
+		{remote: "192.0.2.1:1234", xff: []string{"10.9.9.9, 10.1.2.3"}, expect: "10.9.9.9"},
+		{remote: "[::ffff:10.0.0.1]:1234", xff: []string{"2001:db8::1"}, expect: "2001:db8::1"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		for _, v := range c.xff {
+			req.Header.Add("X-Forwarded-For", v)
+		}
+		g.Expect(ClientIP(req, trustedProxies)).To(Equal(c.expect), c.remote)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyLimitMetaKey is the route metadata key under which a route's
+// *ConcurrencyLimit is held (see Route.WithConcurrencyLimit).
+const ConcurrencyLimitMetaKey = "concurrencyLimit"
+
+// ConcurrencyLimit limits how many requests are served at once, shedding the
+// excess load, e.g.
+//
+//	reports := &httprouter.ConcurrencyLimit{MaxInFlight: 4, MaxQueue: 10}
+//	router.GET("/reports/:id", report).WithConcurrencyLimit(reports)
+//
+// Requests beyond MaxInFlight wait in a queue for up to QueueTimeout. If the
+// queue is full, or the wait times out, the request is answered using
+// Router.Overloaded. Waiting requests are not necessarily served in the order
+// in which they arrived.
+//
+// All the routes that share a ConcurrencyLimit share its capacity. It must
+// not be altered once it is in use.
+type ConcurrencyLimit struct {
+	// MaxInFlight is the maximum number of requests being served at once.
+	MaxInFlight int
+	// MaxQueue is the maximum number of requests waiting to be served. If it
+	// is zero, excess requests are rejected immediately.
+	MaxQueue int
+	// QueueTimeout is how long requests may wait in the queue. If it is
+	// zero, DefaultQueueTimeout is used.
+	QueueTimeout time.Duration
+	// RetryAfter is the delay suggested to rejected clients in the
+	// Retry-After header. If it is zero, one second is used.
+	RetryAfter time.Duration
+
+	once     sync.Once
+	slots    chan struct{}
+	queued   atomic.Int64
+	rejected atomic.Uint64
+}
+
+// DefaultQueueTimeout is how long requests wait for a ConcurrencyLimit that
+// has no QueueTimeout.
+var DefaultQueueTimeout = time.Second
+
+// InFlight gets the number of requests currently being served.
+func (c *ConcurrencyLimit) InFlight() int {
+	return len(c.init())
+}
+
+// Queued gets the number of requests currently waiting to be served.
+func (c *ConcurrencyLimit) Queued() int {
+	return int(c.queued.Load())
+}
+
+// Rejected gets the number of requests that have been rejected.
+func (c *ConcurrencyLimit) Rejected() uint64 {
+	return c.rejected.Load()
+}
+
+func (c *ConcurrencyLimit) init() chan struct{} {
+	c.once.Do(func() {
+		c.slots = make(chan struct{}, c.MaxInFlight)
+	})
+	return c.slots
+}
+
+// acquire takes a slot, waiting in the queue if necessary. It returns false
+// if the request is rejected.
+func (c *ConcurrencyLimit) acquire(req *http.Request) bool {
+	slots := c.init()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if c.queued.Add(1) > int64(c.MaxQueue) {
+		c.queued.Add(-1)
+		c.rejected.Add(1)
+		return false
+	}
+	defer c.queued.Add(-1)
+
+	timeout := c.QueueTimeout
+	if timeout <= 0 {
+		timeout = DefaultQueueTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+	case <-req.Context().Done():
+	}
+
+	c.rejected.Add(1)
+	return false
+}
+
+func (c *ConcurrencyLimit) release() {
+	<-c.slots
+}
+
+// reject answers a request that could not be served.
+func (c *ConcurrencyLimit) reject(w http.ResponseWriter, req *http.Request, overloaded http.Handler) {
+	retryAfter := c.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	w.Header().Set("Retry-After", ceilSeconds(retryAfter))
+
+	if overloaded != nil {
+		overloaded.ServeHTTP(w, req)
+	} else {
+		http.Error(w,
+			http.StatusText(http.StatusServiceUnavailable),
+			http.StatusServiceUnavailable,
+		)
+	}
+}
+
+// Middleware limits the requests served by the next handler. It is a
+// Middleware suitable for Router.Use, for limits that apply to the whole
+// router. Rejected requests are answered with 503 Service Unavailable.
+func (c *ConcurrencyLimit) Middleware(next http.Handler) http.Handler {
+	c.validate()
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !c.acquire(req) {
+			c.reject(w, req, nil)
+			return
+		}
+		defer c.release()
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (c *ConcurrencyLimit) validate() {
+	if c.MaxInFlight < 1 {
+		panic("concurrency limit must have positive MaxInFlight")
+	}
+}
+
+// WithConcurrencyLimit applies a concurrency limit to the route. If the route
+// also has a timeout (see Route.WithTimeout), time spent waiting in the queue
+// counts towards it, and a handle that is still running when the timeout
+// expires keeps its slot until it returns.
+//
+// The limit is also held in the route's metadata under
+// ConcurrencyLimitMetaKey, so that it is visible in route listings and to
+// middleware. A nil limit removes the concurrency limit.
+func (rt *Route) WithConcurrencyLimit(limit *ConcurrencyLimit) *Route {
+	if limit != nil {
+		limit.validate()
+	}
+
+	return rt.refine(func() {
+		if limit == nil {
+			delete(rt.info.Metadata, ConcurrencyLimitMetaKey)
+		} else {
+			rt.WithMeta(ConcurrencyLimitMetaKey, limit)
+		}
+	})
+}
+
+// ConcurrencyLimit gets the concurrency limit of the route, or nil if it has
+// none.
+func (rt *Route) ConcurrencyLimit() *ConcurrencyLimit {
+	limit, _ := rt.Meta(ConcurrencyLimitMetaKey).(*ConcurrencyLimit)
+	return limit
+}
+
+// withConcurrencyLimit wraps the handle so that its concurrency is limited.
+func (r *Router) withConcurrencyLimit(handle Handle, limit *ConcurrencyLimit) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		if !limit.acquire(req) {
+			limit.reject(w, req, r.Overloaded)
+			return
+		}
+		defer limit.release()
+		handle(w, req, ps)
+	}
+}
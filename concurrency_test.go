@@ -0,0 +1,157 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingRouter has a route whose handle waits until released.
+func blockingRouter(limit *ConcurrencyLimit) (*Router, chan struct{}, chan struct{}) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	router := New()
+	router.GET("/report", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		started <- struct{}{}
+		<-release
+	}).WithConcurrencyLimit(limit)
+	return router, started, release
+}
+
+func TestRoute_WithConcurrencyLimit_sheds_load(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 1, RetryAfter: 5 * time.Second}
+	router, started, release := blockingRouter(limit)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+	}()
+	<-started
+	g.Expect(limit.InFlight()).To(Equal(1))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Header().Get("Retry-After")).To(Equal("5"))
+	g.Expect(limit.Rejected()).To(Equal(uint64(1)))
+
+	close(release)
+	wg.Wait()
+	g.Expect(limit.InFlight()).To(Equal(0))
+}
+
+func TestRoute_WithConcurrencyLimit_queue(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Minute}
+	router, started, release := blockingRouter(limit)
+
+	codes := make(chan int, 2)
+	serve := func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+		codes <- w.Code
+	}
+
+	go serve()
+	<-started
+	go serve()
+	g.Eventually(limit.Queued).Should(Equal(1))
+
+	// the queue is full
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Header().Get("Retry-After")).To(Equal("1"))
+
+	close(release)
+	g.Expect(<-codes).To(Equal(http.StatusOK))
+	g.Expect(<-codes).To(Equal(http.StatusOK))
+	g.Expect(limit.Queued()).To(Equal(0))
+	g.Expect(limit.Rejected()).To(Equal(uint64(1)))
+}
+
+func TestRoute_WithConcurrencyLimit_queue_timeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 1, MaxQueue: 5, QueueTimeout: 10 * time.Millisecond}
+	router, started, release := blockingRouter(limit)
+	router.Overloaded = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer close(release)
+
+	go router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+	<-started
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	g.Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+}
+
+func TestRoute_WithConcurrencyLimit_timeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 1}
+	router, started, release := blockingRouter(limit)
+	router.Routes()[0].WithTimeout(10 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Header().Get("Retry-After")).To(BeEmpty())
+	<-started
+
+	// the slow handle is still running, so it still holds the only slot
+	g.Expect(limit.InFlight()).To(Equal(1))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Header().Get("Retry-After")).To(Equal("1"))
+	g.Expect(limit.Rejected()).To(Equal(uint64(1)))
+
+	close(release)
+	g.Eventually(limit.InFlight).Should(Equal(0))
+}
+
+func TestConcurrencyLimit_Middleware(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 2}
+	router := New()
+	router.Use(limit.Middleware)
+	router.GET("/a", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		g.Expect(limit.InFlight()).To(Equal(1))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+
+	g.Expect(func() { (&ConcurrencyLimit{}).Middleware(router) }).To(Panic())
+}
+
+func TestGroup_WithConcurrencyLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 3}
+	router := New()
+	reports := router.Group("/reports").WithConcurrencyLimit(limit)
+	reports.GET("/daily", noop)
+	reports.GET("/weekly", noop)
+
+	for _, rt := range router.Routes() {
+		g.Expect(rt.ConcurrencyLimit()).To(BeIdenticalTo(limit), rt.Path())
+	}
+}
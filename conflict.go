@@ -0,0 +1,136 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ConflictError describes a route that cannot be registered because it
+// conflicts with an existing route. Handle panics with a *ConflictError in
+// this case; TryHandle and Validate return it instead.
+type ConflictError struct {
+	// Method is the method of the new route.
+	Method string
+	// Path is the path of the new route.
+	Path string
+	// Existing is the path of an existing route that conflicts with the new
+	// one. If there is no such route, it is the existing prefix of the path.
+	Existing string
+	// Segment is the part of the new path that conflicts, e.g. a wildcard.
+	// It is blank if the paths are the same.
+	Segment string
+
+	msg string
+}
+
+func newConflict(method, path, existing, segment, msg string) *ConflictError {
+	return &ConflictError{Method: method, Path: path, Existing: existing, Segment: segment, msg: msg}
+}
+
+func (e *ConflictError) Error() string {
+	return e.msg
+}
+
+// TryHandle is like Handle but returns an error instead of panicking if the
+// route cannot be registered. If the route conflicts with an existing route,
+// the error is a *ConflictError; otherwise the method or path is invalid. If
+// there is an error, the router is unchanged.
+//
+// This is intended for routes that are registered from configuration, e.g.
+//
+//	rt, err := router.TryHandle(spec.Method, spec.Path, handle)
+//	var conflict *httprouter.ConflictError
+//	if errors.As(err, &conflict) {
+//		log.Printf("%s conflicts with %s", conflict.Path, conflict.Existing)
+//	}
+func (r *Router) TryHandle(method, path string, handle Handle) (*Route, error) {
+	if handle == nil {
+		return nil, errors.New("handle must not be nil")
+	}
+	if err := r.check(r.routes, RouteSpec{Method: method, Path: path}); err != nil {
+		return nil, err
+	}
+
+	// a conflict is found before anything is registered
+	if r.tree != nil && len(r.routes[routeKey{method: method, path: path}]) == 0 {
+		if conflict := r.tree.conflicts(method, path); conflict != nil {
+			return nil, conflict
+		}
+	}
+
+	return r.Handle(method, path, handle), nil
+}
+
+// RouteSpec specifies a route for Validate.
+type RouteSpec struct {
+	Method, Path string
+}
+
+// Validate checks whether the routes could all be registered with this router,
+// without registering them. The routes are checked against the existing
+// routes and against each other, in order. An error is returned for each
+// route that could not be registered, i.e. a *ConflictError for each conflict,
+// or another error if the route is invalid. So all the problems are reported
+// at once, instead of Handle panicking on the first of them.
+//
+// The routes that could be registered are assumed to be, when checking the
+// routes that follow them.
+func (r *Router) Validate(routes ...RouteSpec) []error {
+	var errs []error
+
+	// a scratch router holds a copy of the tree
+	trial := &Router{tree: &node{}, routes: make(map[routeKey][]*Route, len(r.routes)+len(routes))}
+	if r.tree != nil {
+		trial.tree = r.tree.clone()
+	}
+	for key, rts := range r.routes {
+		trial.routes[key] = rts
+	}
+
+	for _, spec := range routes {
+		if err := trial.check(trial.routes, spec); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		key := routeKey{method: spec.Method, path: spec.Path}
+		if len(trial.routes[key]) == 0 {
+			if conflict := trial.tree.tryAddRoute(spec.Method, spec.Path, validated); conflict != nil {
+				errs = append(errs, conflict)
+				continue
+			}
+		}
+		trial.routes[key] = append(trial.routes[key][:len(trial.routes[key]):len(trial.routes[key])], newRoute(r, spec.Method, spec.Path, nil))
+	}
+
+	return errs
+}
+
+// validated is the handle held in Validate's scratch trees.
+func validated(http.ResponseWriter, *http.Request, Params) {}
+
+// check finds the problems with a route that can be found without adding it
+// to a tree. The routes are those already registered.
+func (r *Router) check(routes map[routeKey][]*Route, spec RouteSpec) error {
+	if spec.Method == "" {
+		return errors.New("method must not be empty")
+	}
+	if len(spec.Path) < 1 || spec.Path[0] != '/' {
+		return errors.New("path must begin with '/' in path '" + spec.Path + "'")
+	}
+	if msg := checkWildcards(spec.Path); msg != "" {
+		return errors.New(msg)
+	}
+
+	for _, rt := range routes[routeKey{method: spec.Method, path: spec.Path}] {
+		if !rt.conditional() {
+			return newConflict(spec.Method, spec.Path, rt.path, "",
+				"a handle is already registered for path '"+spec.Path+"'")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRouter_TryHandle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop)
+	router.GET("/files/*filepath", noop)
+	router.GET("/items/", noop)
+
+	cases := []struct {
+		method, path      string
+		existing, segment string
+	}{
+		{method: http.MethodGet, path: "/users/:id", existing: "/users/:id"},
+		{method: http.MethodGet, path: "/users/:name", existing: "/users/:id", segment: ":name"},
+		{method: http.MethodPost, path: "/users/new", existing: "/users/:id", segment: "new"},
+		{method: http.MethodGet, path: "/files/readme", existing: "/files/*filepath", segment: "/readme"},
+		{method: http.MethodGet, path: "/items/*rest", existing: "/items/", segment: "*rest"},
+	}
+
+	for _, c := range cases {
+		rt, err := router.TryHandle(c.method, c.path, noop)
+		g.Expect(rt).To(BeNil(), c.path)
+
+		var conflict *ConflictError
+		g.Expect(errors.As(err, &conflict)).To(BeTrue(), c.path)
+		g.Expect(conflict.Method).To(Equal(c.method), c.path)
+		g.Expect(conflict.Path).To(Equal(c.path), c.path)
+		g.Expect(conflict.Existing).To(Equal(c.existing), c.path)
+		g.Expect(conflict.Segment).To(Equal(c.segment), c.path)
+	}
+
+	// the router is unchanged by the failures
+	g.Expect(router.Routes()).To(HaveLen(3))
+	g.Expect(router.ListPaths("")).To(Equal(map[string][]string{
+		http.MethodGet: {"/files/*filepath", "/items/", "/users/:id"},
+	}))
+
+	rt, err := router.TryHandle(http.MethodPost, "/users/:id", noop)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rt.Method()).To(Equal(http.MethodPost))
+	g.Expect(serveStatic(router, http.MethodPost, "/users/1").Code).To(Equal(http.StatusOK))
+}
+
+func TestRouter_TryHandle_treeUnchanged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/src/*filepath", noop)
+	router.GET("/user/:name", noop)
+	before := treeShape(router.tree)
+
+	// each of these would split a node before the conflict is found
+	for _, path := range []string{"/sr:x", "/user/new", "/us:x/y"} {
+		_, err := router.TryHandle(http.MethodGet, path, noop)
+		g.Expect(err).To(HaveOccurred(), path)
+	}
+
+	g.Expect(treeShape(router.tree)).To(Equal(before))
+	g.Expect(router.tree.priority).To(Equal(uint32(2)))
+	checkPriorities(g, router.tree)
+}
+
+// treeShape describes the nodes of the tree, one per line.
+func treeShape(n *node) string {
+	shape := fmt.Sprintf("%q %q %d %d\n", n.path, n.indices, n.priority, len(n.handles))
+	for _, child := range n.children {
+		for _, line := range strings.SplitAfter(treeShape(child), "\n") {
+			if line != "" {
+				shape += "  " + line
+			}
+		}
+	}
+	return shape
+}
+
+func TestRouter_TryHandle_invalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+
+	_, err := router.TryHandle("", "/", noop)
+	g.Expect(err).To(MatchError("method must not be empty"))
+
+	_, err = router.TryHandle(http.MethodGet, "x", noop)
+	g.Expect(err).To(MatchError("path must begin with '/' in path 'x'"))
+
+	_, err = router.TryHandle(http.MethodGet, "/", nil)
+	g.Expect(err).To(MatchError("handle must not be nil"))
+
+	_, err = router.TryHandle(http.MethodGet, "/:a:b", noop)
+	g.Expect(err).To(MatchError(ContainSubstring("only one wildcard per path segment is allowed")))
+
+	g.Expect(router.Routes()).To(BeEmpty())
+
+	// variants of conditional routes are allowed
+	router.GET("/x", noop).Produces("text/html")
+	_, err = router.TryHandle(http.MethodGet, "/x", noop)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = router.TryHandle(http.MethodGet, "/x", noop)
+	g.Expect(err).To(BeAssignableToTypeOf(&ConflictError{}))
+}
+
+func TestRouter_Handle_conflictPanic(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/a/:id", noop)
+
+	recv := catchPanic(func() { router.GET("/a/:name", noop) })
+	g.Expect(recv).To(BeAssignableToTypeOf(&ConflictError{}))
+	g.Expect(recv.(error).Error()).To(Equal("':name' in new path '/a/:name' conflicts with existing wildcard ':id' in existing prefix '/a/:id'"))
+}
+
+func TestRouter_Validate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop)
+
+	errs := router.Validate(
+		RouteSpec{Method: http.MethodGet, Path: "/users/:id/posts"},
+		RouteSpec{Method: http.MethodGet, Path: "/users/:name/friends"},
+		RouteSpec{Method: http.MethodGet, Path: "/a/:x/:"},
+		RouteSpec{Method: http.MethodGet, Path: "/a/:x"},
+		RouteSpec{Method: http.MethodPut, Path: "/users/:id/posts"},
+		RouteSpec{Method: http.MethodPut, Path: "/users/:id/posts"},
+		RouteSpec{Method: http.MethodGet, Path: "bad"},
+	)
+
+	g.Expect(errs).To(HaveLen(4))
+
+	var conflict *ConflictError
+	g.Expect(errors.As(errs[0], &conflict)).To(BeTrue())
+	g.Expect(conflict.Path).To(Equal("/users/:name/friends"))
+	g.Expect(conflict.Existing).To(Equal("/users/:id"))
+	g.Expect(conflict.Segment).To(Equal(":name"))
+
+	g.Expect(errs[1]).To(MatchError("wildcards must be named with a non-empty name in path '/a/:x/:'"))
+
+	g.Expect(errors.As(errs[2], &conflict)).To(BeTrue())
+	g.Expect(conflict.Method).To(Equal(http.MethodPut))
+	g.Expect(conflict.Existing).To(Equal("/users/:id/posts"))
+
+	g.Expect(errs[3]).To(MatchError("path must begin with '/' in path 'bad'"))
+
+	// nothing was registered
+	g.Expect(router.Routes()).To(HaveLen(1))
+
+	g.Expect(New().Validate(RouteSpec{Method: http.MethodGet, Path: "/"})).To(BeEmpty())
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// connectRoute is a CONNECT route, matched against the authority-form
+// request target (host:port) rather than against a path.
+type connectRoute struct {
+	host, port string
+	handle     Handle
+}
+
+// CONNECT registers a handle for CONNECT requests, which are typically used by
+// forward proxies. CONNECT requests have an authority-form target ("host:port")
+// instead of a path, so these routes are matched separately from the routing
+// tree.
+//
+// The authority must have the form "host:port". The host may be "*" to match
+// any host, or may start with "*." to match any sub-domain, e.g.
+// "*.example.com". The port may be "*" to match any port.
+//
+// The matched host and port are passed to the handle as the "host" and "port"
+// Params. Routes are tried in the order they were registered.
+//
+// CONNECT support is opt-in: without any CONNECT routes, CONNECT requests are
+// routed by path like any other method (and so normally receive 404 or 405).
+func (r *Router) CONNECT(authority string, handle Handle) {
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+
+	host, port, err := net.SplitHostPort(authority)
+	if err != nil || host == "" || port == "" {
+		panic("authority must be 'host:port' in '" + authority + "'")
+	}
+
+	if strings.Contains(strings.TrimPrefix(host, "*"), "*") || strings.Contains(strings.TrimPrefix(port, "*"), "*") {
+		panic("'*' is only allowed as a prefix of the host or as the whole port in '" + authority + "'")
+	}
+
+	if host != "*" && strings.HasPrefix(host, "*") && !strings.HasPrefix(host, "*.") {
+		panic("host wildcard must be '*' or '*.domain' in '" + authority + "'")
+	}
+
+	r.connect = append(r.connect, connectRoute{host: strings.ToLower(host), port: port, handle: handle})
+}
+
+func (cr connectRoute) match(host, port string) bool {
+	if cr.port != "*" && cr.port != port {
+		return false
+	}
+
+	switch {
+	case cr.host == "*":
+		return true
+	case strings.HasPrefix(cr.host, "*."):
+		return strings.HasSuffix(host, cr.host[1:])
+	default:
+		return cr.host == host
+	}
+}
+
+// serveCONNECT attempts to serve a CONNECT request using the authority-form
+// routes.
+func (r *Router) serveCONNECT(w http.ResponseWriter, req *http.Request) bool {
+	authority := req.Host
+	if req.URL != nil && req.URL.Host != "" {
+		authority = req.URL.Host
+	}
+
+	host, port, err := net.SplitHostPort(authority)
+	if err != nil {
+		return false
+	}
+	host = strings.ToLower(host)
+
+	for _, cr := range r.connect {
+		if cr.match(host, port) {
+			r.handleCONNECT(w, req, cr, host, port)
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleCONNECT serves a CONNECT request by the route that matched it.
+func (r *Router) handleCONNECT(w http.ResponseWriter, req *http.Request, cr connectRoute, host, port string) {
+	if r.PanicHandler != nil {
+		if r.tracksCommit {
+			w = committable(w)
+		}
+		defer r.recv(w, req)
+	}
+
+	r.observe(w, req, net.JoinHostPort(cr.host, cr.port), Handled)
+	cr.handle(w, req, Params{{Key: "host", Value: host}, {Key: "port", Value: port}})
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_CONNECT(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	var params Params
+	handle := func(name string) Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps Params) {
+			saw = name
+			params = ps
+		}
+	}
+
+	router := New()
+	router.CONNECT("db.internal:5432", handle("db"))
+	router.CONNECT("*.internal:443", handle("internal"))
+	router.CONNECT("*:*", handle("any"))
+
+	cases := []struct {
+		authority, expected, host, port string
+	}{
+		{"db.internal:5432", "db", "db.internal", "5432"},
+		{"DB.Internal:5432", "db", "db.internal", "5432"},
+		{"api.internal:443", "internal", "api.internal", "443"},
+		{"api.internal:80", "any", "api.internal", "80"},
+		{"example.com:443", "any", "example.com", "443"},
+	}
+
+	for _, c := range cases {
+		saw = ""
+		r := httptest.NewRequest(http.MethodConnect, c.authority, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		g.Expect(saw).To(Equal(c.expected), c.authority)
+		g.Expect(params.ByName("host")).To(Equal(c.host), c.authority)
+		g.Expect(params.ByName("port")).To(Equal(c.port), c.authority)
+	}
+}
+
+func TestRouter_CONNECT_not_matched(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.CONNECT("db.internal:5432", func(w http.ResponseWriter, r *http.Request, ps Params) {})
+
+	r := httptest.NewRequest(http.MethodConnect, "example.com:443", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+}
+
+func TestRouter_CONNECT_panics(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cases := []string{"noport", ":443", "host:", "a*b:443", "*b.com:443", "host:4*"}
+
+	for _, c := range cases {
+		router := New()
+		recv := catchPanic(func() {
+			router.CONNECT(c, func(w http.ResponseWriter, r *http.Request, ps Params) {})
+		})
+		g.Expect(recv).NotTo(BeNil(), c)
+	}
+}
+
+func TestRouter_CONNECT_PanicHandler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, r *http.Request, rcv interface{}) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("recovered"))
+	}
+	router.CONNECT("db.internal:5432", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		panic("oops")
+	})
+
+	r := httptest.NewRequest(http.MethodConnect, "db.internal:5432", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusBadGateway))
+	g.Expect(w.Body.String()).To(Equal("recovered"))
+}
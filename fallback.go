@@ -0,0 +1,149 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+)
+
+// Fallback adds handlers that are tried in turn for requests that match no
+// route, before the NotFound handler, e.g.
+//
+//	router.Fallback(legacyRouter, legacyProxy)
+//
+// This allows routes to be migrated incrementally from other routing code.
+// Each handler either answers the request or declines it by responding with
+// 404 Not Found (see Decline), in which case its response is discarded,
+// including any headers it set, and the next handler is tried. So a Router
+// that has no NotFound handler of its own can be used as a fallback.
+//
+// The response of the last handler is always passed on, even if it is 404 Not
+// Found, so that it can be, say, a reverse proxy whose 404 replies reach the
+// client. So, once there are fallback handlers, the NotFound handlers of this
+// router and of any mounted routers are not used for requests that match no
+// route. They are still used by routes that reject a request, i.e. by
+// StaticFiles for missing files, dotfiles and disabled listings, and by a
+// VersionedAPI for unknown versions.
+//
+// Fallbacks are not tried for requests that are answered with 405 Method Not
+// Allowed, nor for automatic OPTIONS replies.
+//
+// Each handler is given the same request, so a handler that declines must not
+// have read the request body, because the next handler would find it drained.
+//
+// Fallback is not concurrency-safe; it should be called while the routes are
+// being registered.
+func (r *Router) Fallback(handlers ...http.Handler) {
+	for _, h := range handlers {
+		if h == nil {
+			panic("fallback handler must not be nil")
+		}
+	}
+	r.fallbacks = append(r.fallbacks, handlers...)
+}
+
+// Decline is the sentinel response by which a handler passed to
+// Router.Fallback declines a request, so that the next one is tried. It
+// simply writes the 404 Not Found status, so from the last handler, or
+// elsewhere, it is an ordinary 404 response, albeit without a body.
+func Decline(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// fallBack tries the fallback handlers in turn, until one of them accepts the
+// request. The last one answers it regardless.
+func (r *Router) fallBack(w http.ResponseWriter, req *http.Request) {
+	r.observe(w, req, "", NotFound) // unless a handler accepts it
+
+	last := len(r.fallbacks) - 1
+	for i, h := range r.fallbacks {
+		fw := &fallbackWriter{w: w, h: make(http.Header), final: i == last}
+
+		// the handler may alter the URL, e.g. if it is a Router that redirects
+		r2 := new(http.Request)
+		*r2 = *req
+		u := *req.URL
+		r2.URL = &u
+
+		h.ServeHTTP(fw, r2)
+
+		if !fw.declined {
+			r.observe(w, req, "", FellBack)
+			if !fw.wroteHeader {
+				// nothing was written, which implies 200 OK
+				copyHeader(w.Header(), fw.h)
+				w.WriteHeader(http.StatusOK)
+			}
+			return
+		}
+	}
+}
+
+// fallbackWriter passes the response through to the underlying writer, unless
+// the status is 404, in which case the response is discarded, except from the
+// final handler. The handler has its own header map, which is copied when the
+// header is written, so that a declined response leaves no trace.
+type fallbackWriter struct {
+	w           http.ResponseWriter
+	h           http.Header
+	final       bool
+	wroteHeader bool
+	declined    bool
+}
+
+// discards is true if the handler has declined and its response is not
+// passed on.
+func (fw *fallbackWriter) discards() bool {
+	return fw.declined && !fw.final
+}
+
+func (fw *fallbackWriter) Header() http.Header {
+	return fw.h
+}
+
+func (fw *fallbackWriter) WriteHeader(code int) {
+	if fw.wroteHeader {
+		return
+	}
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		// informational responses don't commit the response
+		copyHeader(fw.w.Header(), fw.h)
+		fw.w.WriteHeader(code)
+		return
+	}
+
+	fw.wroteHeader = true
+	fw.declined = code == http.StatusNotFound
+	if fw.discards() {
+		return
+	}
+	copyHeader(fw.w.Header(), fw.h)
+	fw.w.WriteHeader(code)
+}
+
+func (fw *fallbackWriter) Write(p []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if fw.discards() {
+		return len(p), nil
+	}
+	return fw.w.Write(p)
+}
+
+// Flush implements http.Flusher so that streamed responses are not delayed.
+func (fw *fallbackWriter) Flush() {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := fw.w.(http.Flusher); ok && !fw.discards() {
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (fw *fallbackWriter) Unwrap() http.ResponseWriter {
+	return fw.w
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRouter_Fallback(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	legacy := New()
+	legacy.GET("/old/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		w.Header().Set("X-Legacy", ps.ByName("id"))
+		w.Write([]byte("legacy"))
+	})
+
+	var tried []string
+	declining := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tried = append(tried, req.URL.Path)
+		w.Header().Set("X-Declined", "yes")
+		Decline(w)
+	})
+	last := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/proxied" {
+			w.WriteHeader(http.StatusAccepted)
+		} else {
+			http.NotFound(w, req)
+		}
+	})
+
+	router := New()
+	router.NotFound = statusHandler(http.StatusGone)
+	router.GET("/new", noop)
+	router.Fallback(legacy, declining)
+	router.Fallback(last)
+
+	w := serveStatic(router, http.MethodGet, "/new")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(tried).To(BeEmpty())
+
+	w = serveStatic(router, http.MethodGet, "/old/3")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("legacy"))
+	g.Expect(w.Header().Get("X-Legacy")).To(Equal("3"))
+	g.Expect(tried).To(BeEmpty())
+
+	w = serveStatic(router, http.MethodGet, "/proxied")
+	g.Expect(w.Code).To(Equal(http.StatusAccepted))
+	g.Expect(w.Header().Get("X-Declined")).To(BeEmpty())
+	g.Expect(tried).To(Equal([]string{"/proxied"}))
+
+	// the last handler's 404 is passed on, instead of NotFound
+	w = serveStatic(router, http.MethodGet, "/nowhere")
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+	g.Expect(w.Body.String()).To(Equal("404 page not found\n"))
+	g.Expect(w.Header().Get("X-Declined")).To(BeEmpty())
+
+	// not tried for 405
+	w = serveStatic(router, http.MethodPost, "/new")
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(tried).To(HaveLen(2))
+}
+
+func TestRouter_Fallback_unchangedRequest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the legacy router redirects, altering its copy of the URL
+	legacy := New()
+	legacy.GET("/a/", noop)
+
+	var path string
+	router := New()
+	router.Fallback(legacy, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+	}))
+
+	w := serveStatic(router, http.MethodGet, "/a")
+	g.Expect(w.Code).To(Equal(http.StatusMovedPermanently))
+
+	w = serveStatic(router, http.MethodGet, "/b")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(path).To(Equal("/b"))
+}
+
+func TestRouter_Fallback_observed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	obs := &recordingObserver{}
+	router := New()
+	router.Observer = obs
+	declining := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		Decline(w)
+	})
+	router.Fallback(declining, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/x" {
+			Decline(w)
+		}
+	}))
+
+	serveStatic(router, http.MethodGet, "/x")
+	serveStatic(router, http.MethodGet, "/y")
+
+	// the outcome is only FellBack once a handler has accepted the request
+	g.Expect(obs.started).To(HaveLen(2))
+	g.Expect(obs.started[0].Outcome).To(Equal(NotFound))
+	g.Expect(obs.started[1].Outcome).To(Equal(NotFound))
+
+	g.Expect(obs.ended).To(HaveLen(2))
+	g.Expect(obs.ended[0].Outcome).To(Equal(FellBack))
+	g.Expect(obs.ended[0].Status).To(Equal(http.StatusOK))
+	g.Expect(obs.ended[1].Outcome).To(Equal(NotFound))
+	g.Expect(obs.ended[1].Status).To(Equal(http.StatusNotFound))
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"time"
+)
+
+// Group registers routes that share a path prefix and settings, e.g.
+//
+//	api := router.Group("/api").WithTimeout(time.Second)
+//	api.GET("/users/:id", getUser)                   // i.e. /api/users/:id
+//	api.POST("/uploads", upload).WithTimeout(time.Minute)
+//
+// The group's settings are applied to each route as it is registered, so
+// they should be set before the routes are registered. Each route can
+// override them individually.
+type Group struct {
+	router  *Router
+	prefix  string
+	timeout time.Duration
+
+	maxBodySize    int64
+	hasMaxBodySize bool
+
+	rateLimit        *RateLimit
+	concurrencyLimit *ConcurrencyLimit
+}
+
+// Group creates a group of routes whose paths all start with the prefix,
+// which must begin with '/' and must not end with '/'.
+func (r *Router) Group(prefix string) *Group {
+	return (&Group{router: r}).Group(prefix)
+}
+
+// Group creates a nested group whose paths all start with the group's prefix
+// followed by this prefix. The nested group inherits the group's current
+// settings.
+func (g *Group) Group(prefix string) *Group {
+	if len(prefix) < 2 || prefix[0] != '/' || prefix[len(prefix)-1] == '/' {
+		panic("prefix must begin with '/' and must not end with '/' in prefix '" + prefix + "'")
+	}
+	nested := *g
+	nested.prefix = g.prefix + prefix
+	return &nested
+}
+
+// Prefix gets the path prefix of the group.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// WithTimeout sets the timeout for routes subsequently registered with the
+// group (see Route.WithTimeout).
+func (g *Group) WithTimeout(d time.Duration) *Group {
+	g.timeout = d
+	return g
+}
+
+// WithMaxBodySize sets the maximum request body size for routes subsequently
+// registered with the group, overriding Router.MaxBodySize (see
+// Route.WithMaxBodySize).
+func (g *Group) WithMaxBodySize(n int64) *Group {
+	g.maxBodySize, g.hasMaxBodySize = n, true
+	return g
+}
+
+// WithRateLimit sets the rate limit for routes subsequently registered with
+// the group (see Route.WithRateLimit). Each route has its own buckets.
+func (g *Group) WithRateLimit(limit *RateLimit) *Group {
+	g.rateLimit = limit
+	return g
+}
+
+// WithConcurrencyLimit sets the concurrency limit for routes subsequently
+// registered with the group (see Route.WithConcurrencyLimit). The routes
+// share the limit's capacity.
+func (g *Group) WithConcurrencyLimit(limit *ConcurrencyLimit) *Group {
+	g.concurrencyLimit = limit
+	return g
+}
+
+// Handle registers a new request handle with the given method and with the
+// path appended to the group's prefix. See Router.Handle.
+func (g *Group) Handle(method, path string, handle Handle) *Route {
+	return g.apply(g.router.Handle(method, g.prefix+path, handle))
+}
+
+// Handler registers a http.Handler with the given method and with the path
+// appended to the group's prefix. See Router.Handler.
+func (g *Group) Handler(method, path string, handler http.Handler) *Route {
+	return g.apply(g.router.Handler(method, g.prefix+path, handler))
+}
+
+// HandlerFunc registers a http.HandlerFunc with the given method and with the
+// path appended to the group's prefix. See Router.HandlerFunc.
+func (g *Group) HandlerFunc(method, path string, handler http.HandlerFunc) *Route {
+	return g.Handler(method, path, handler)
+}
+
+// GET is a shortcut for group.Handle(http.MethodGet, path, handle)
+func (g *Group) GET(path string, handle Handle) *Route {
+	return g.Handle(http.MethodGet, path, handle)
+}
+
+// HEAD is a shortcut for group.Handle(http.MethodHead, path, handle)
+func (g *Group) HEAD(path string, handle Handle) *Route {
+	return g.Handle(http.MethodHead, path, handle)
+}
+
+// OPTIONS is a shortcut for group.Handle(http.MethodOptions, path, handle)
+func (g *Group) OPTIONS(path string, handle Handle) *Route {
+	return g.Handle(http.MethodOptions, path, handle)
+}
+
+// POST is a shortcut for group.Handle(http.MethodPost, path, handle)
+func (g *Group) POST(path string, handle Handle) *Route {
+	return g.Handle(http.MethodPost, path, handle)
+}
+
+// PUT is a shortcut for group.Handle(http.MethodPut, path, handle)
+func (g *Group) PUT(path string, handle Handle) *Route {
+	return g.Handle(http.MethodPut, path, handle)
+}
+
+// PATCH is a shortcut for group.Handle(http.MethodPatch, path, handle)
+func (g *Group) PATCH(path string, handle Handle) *Route {
+	return g.Handle(http.MethodPatch, path, handle)
+}
+
+// DELETE is a shortcut for group.Handle(http.MethodDelete, path, handle)
+func (g *Group) DELETE(path string, handle Handle) *Route {
+	return g.Handle(http.MethodDelete, path, handle)
+}
+
+// apply applies the group's settings to a newly-registered route.
+func (g *Group) apply(rt *Route) *Route {
+	if g.rateLimit != nil {
+		rt.WithRateLimit(g.rateLimit)
+	}
+	if g.concurrencyLimit != nil {
+		rt.WithConcurrencyLimit(g.concurrencyLimit)
+	}
+	return rt.refine(func() {
+		rt.timeout = g.timeout
+		if g.hasMaxBodySize {
+			rt.maxBodySize = g.maxBodySize
+		}
+	})
+}
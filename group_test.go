@@ -0,0 +1,70 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGroup_prefix(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var got []string
+	router := New()
+	api := router.Group("/api")
+	v1 := api.Group("/v1")
+
+	api.GET("/status", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		got = append(got, "status")
+	})
+	v1.PUT("/users/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		got = append(got, "user "+ps.ByName("id"))
+	})
+	v1.HandlerFunc(http.MethodDelete, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		got = append(got, "delete "+RouteFromContext(req.Context()).Pattern)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/status", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/api/v1/users/7", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/users/7", nil))
+
+	g.Expect(v1.Prefix()).To(Equal("/api/v1"))
+	g.Expect(got).To(Equal([]string{"status", "user 7", "delete /api/v1/users/:id"}))
+}
+
+func TestGroup_bad_prefix(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	for _, prefix := range []string{"", "/", "api", "/api/"} {
+		g.Expect(func() { router.Group(prefix) }).To(Panic(), prefix)
+	}
+}
+
+func TestGroup_WithTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	api := router.Group("/api").WithTimeout(200 * time.Millisecond)
+	lookups := api.Group("/lookups")
+	lookups.GET("/:id", noop)
+	api.POST("/uploads", noop).WithTimeout(30 * time.Second)
+	router.GET("/other", noop)
+
+	timeouts := make(map[string]time.Duration)
+	for _, rt := range router.Routes() {
+		timeouts[rt.Path()] = rt.Timeout()
+	}
+
+	g.Expect(timeouts).To(Equal(map[string]time.Duration{
+		"/api/lookups/:id": 200 * time.Millisecond,
+		"/api/uploads":     30 * time.Second,
+		"/other":           0,
+	}))
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// IsHead returns true if the request is a HEAD request, so that handlers can
+// skip generating a response body that would be discarded anyway. This is
+// particularly useful for GET handlers that also serve HEAD requests, which
+// see the request method unchanged.
+func IsHead(req *http.Request) bool {
+	return req.Method == http.MethodHead
+}
+
+// headViaGet wraps a GET handle so that it serves a HEAD request. The response
+// body is discarded but its length is counted so that the Content-Length header
+// can be set.
+func headViaGet(handle Handle) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		hw := &headResponseWriter{ResponseWriter: w}
+		handle(hw, req, ps)
+		hw.commit()
+	}
+}
+
+// headResponseWriter discards the response body, counting its length instead.
+// The status code is deferred until the handler has finished, so that the
+// Content-Length header can be set before the header is written.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	length    int64
+	committed bool
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.length += int64(len(p))
+	return len(p), nil
+}
+
+// Flush commits the header early; the Content-Length cannot then be known.
+func (w *headResponseWriter) Flush() {
+	w.commitStatus()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headResponseWriter) commit() {
+	if w.committed {
+		return
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if bodyAllowedForStatus(w.status) {
+		h := w.Header()
+		if h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+			h.Set("Content-Length", strconv.FormatInt(w.length, 10))
+		}
+	}
+
+	w.commitStatus()
+}
+
+func (w *headResponseWriter) commitStatus() {
+	if !w.committed {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.committed = true
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// bodyAllowedForStatus reports whether a given response status code
+// permits a body. See RFC 7230, section 3.3.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_HeadContentLength(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	bodyGenerated := 0
+
+	router := New()
+	router.HeadContentLength = true
+	router.GET("/hello", func(w http.ResponseWriter, r *http.Request, _ Params) {
+		w.Header().Set("Content-Type", "text/plain")
+		if !IsHead(r) {
+			bodyGenerated++
+		}
+		w.Write([]byte("hello "))
+		w.Write([]byte("world"))
+	})
+	router.GET("/empty", func(w http.ResponseWriter, r *http.Request, _ Params) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodHead, "/hello", nil)
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Length")).To(Equal("11"))
+	g.Expect(w.Body.Len()).To(Equal(0))
+	g.Expect(bodyGenerated).To(Equal(0))
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/hello", nil)
+	router.ServeHTTP(w, r)
+	g.Expect(w.Body.String()).To(Equal("hello world"))
+	g.Expect(bodyGenerated).To(Equal(1))
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodHead, "/empty", nil)
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusNoContent))
+	g.Expect(w.Header().Get("Content-Length")).To(Equal(""))
+}
+
+func TestRouter_HeadContentLength_explicit_HEAD_handle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.HeadContentLength = true
+	router.GET("/x", func(w http.ResponseWriter, r *http.Request, _ Params) {
+		w.Write([]byte("get"))
+	})
+	router.HEAD("/x", func(w http.ResponseWriter, r *http.Request, _ Params) {
+		_, wrapped := w.(*headResponseWriter)
+		g.Expect(wrapped).To(BeFalse())
+		g.Expect(IsHead(r)).To(BeTrue())
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodHead, "/x", nil)
+	router.ServeHTTP(w, r)
+	g.Expect(w.Header().Get("Content-Length")).To(Equal(""))
+}
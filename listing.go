@@ -0,0 +1,128 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DirListing describes a directory, for rendering a listing.
+type DirListing struct {
+	// Path is the request path of the directory.
+	Path string `json:"path"`
+	// Entries are the files and sub-directories, sorted by name.
+	Entries []DirEntry `json:"entries"`
+}
+
+// DirEntry describes a file or sub-directory in a DirListing.
+type DirEntry struct {
+	Name    string    `json:"name"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// Href gets the relative URL of the entry.
+func (e DirEntry) Href() string {
+	u := url.URL{Path: e.Name}
+	if e.IsDir {
+		u.Path += "/"
+	}
+	return u.String()
+}
+
+// DefaultListingTemplate renders directory listings as HTML, similar to
+// http.FileServer. Its data is a DirListing.
+var DefaultListingTemplate = template.Must(template.New("listing").Parse(`<!doctype html>
+<meta name="viewport" content="width=device-width">
+<title>{{.Path}}</title>
+<pre>
+{{range .Entries}}<a href="{{.Href}}">{{.Name}}{{if .IsDir}}/{{end}}</a>
+{{end}}</pre>
+`))
+
+// DisableListings prevents directories that have no index.html file from
+// being listed. Requests for them are answered using the router's NotFound
+// handler instead.
+func (sf *StaticFiles) DisableListings() *StaticFiles {
+	sf.noListings = true
+	return sf
+}
+
+// ListingTemplate sets the template used to render directory listings as
+// HTML. Its data is a DirListing. Clients that prefer application/json to
+// text/html, according to their Accept header, are sent the DirListing as
+// JSON instead. By default, DefaultListingTemplate is used.
+func (sf *StaticFiles) ListingTemplate(t *template.Template) *StaticFiles {
+	sf.listing = t
+	return sf
+}
+
+// HideDotfiles hides files and directories whose names start with ".", such
+// as ".git" or ".env". Requests for them, or for anything within them, are
+// answered using the router's NotFound handler, and they are omitted from
+// directory listings.
+func (sf *StaticFiles) HideDotfiles() *StaticFiles {
+	sf.hideDotfiles = true
+	return sf
+}
+
+// isDotfile is true if any element of the file name starts with ".".
+func isDotfile(name string) bool {
+	for _, elem := range strings.Split(name, "/") {
+		if len(elem) > 1 && elem[0] == '.' {
+			return true
+		}
+	}
+	return false
+}
+
+func (sf *StaticFiles) serveListing(w http.ResponseWriter, req *http.Request, name string) {
+	if sf.noListings {
+		sf.route.router.notFound(w, req)
+		return
+	}
+
+	entries, err := fs.ReadDir(sf.fsys, name)
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+
+	listing := DirListing{Path: req.URL.Path, Entries: make([]DirEntry, 0, len(entries))}
+	for _, e := range entries {
+		if sf.hideDotfiles && strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		entry := DirEntry{Name: e.Name(), IsDir: e.IsDir()}
+		if info, err := e.Info(); err == nil {
+			entry.Size, entry.ModTime = info.Size(), info.ModTime()
+		}
+		listing.Entries = append(listing.Entries, entry)
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Accept")
+
+	ranges := parseAccept(req.Header.Values("Accept"))
+	if len(ranges) > 0 && quality("application/json", ranges) > quality("text/html", ranges) {
+		h.Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(listing)
+		return
+	}
+
+	t := sf.listing
+	if t == nil {
+		t = DefaultListingTemplate
+	}
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	t.Execute(w, listing)
+}
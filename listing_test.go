@@ -0,0 +1,132 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStaticFiles_listing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/static/*", testFS)
+
+	w := serveStatic(router, http.MethodGet, "/static/images/")
+
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+	g.Expect(w.Header().Get("Vary")).To(Equal("Accept"))
+	g.Expect(w.Body.String()).To(ContainSubstring(`<a href="logo.svg">logo.svg</a>`))
+	g.Expect(w.Body.String()).To(ContainSubstring(`<a href=".secret.txt">.secret.txt</a>`))
+
+	w = serveStatic(router, http.MethodGet, "/static/docs/guide")
+	g.Expect(w.Code).To(Equal(http.StatusMovedPermanently))
+	g.Expect(w.Header().Get("Location")).To(Equal("guide/"))
+}
+
+func TestStaticFiles_listing_json(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/static/*", testFS)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/static/docs/guide/", nil)
+	req.Header.Set("Accept", "application/json, text/html;q=0.5")
+	router.ServeHTTP(w, req)
+
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var listing DirListing
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &listing)).To(Succeed())
+	g.Expect(listing.Path).To(Equal("/static/docs/guide/"))
+	g.Expect(listing.Entries).To(HaveLen(1))
+	g.Expect(listing.Entries[0].Name).To(Equal("a.txt"))
+	g.Expect(listing.Entries[0].Size).To(Equal(int64(7)))
+}
+
+func TestStaticFiles_ListingTemplate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/static/*", testFS).
+		ListingTemplate(template.Must(template.New("x").Parse(`{{.Path}}:{{range .Entries}} {{.Href}}{{end}}`)))
+
+	w := serveStatic(router, http.MethodGet, "/static/docs/")
+	g.Expect(w.Body.String()).To(Equal("<p>docs</p>"))
+
+	w = serveStatic(router, http.MethodGet, "/static/images/")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("/static/images/: .secret.txt logo.svg"))
+}
+
+func TestStaticFiles_DisableListings(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	router.ServeFS("/static/*", testFS).DisableListings()
+
+	g.Expect(serveStatic(router, http.MethodGet, "/static/images/").Code).To(Equal(http.StatusGone))
+	g.Expect(serveStatic(router, http.MethodGet, "/static/docs/").Code).To(Equal(http.StatusOK))
+	g.Expect(serveStatic(router, http.MethodGet, "/static/images/logo.svg").Code).To(Equal(http.StatusOK))
+}
+
+func TestStaticFiles_HideDotfiles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/static/*", testFS).HideDotfiles()
+
+	g.Expect(serveStatic(router, http.MethodGet, "/static/images/.secret.txt").Code).To(Equal(http.StatusNotFound))
+
+	w := serveStatic(router, http.MethodGet, "/static/images/")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(ContainSubstring("logo.svg"))
+	g.Expect(w.Body.String()).NotTo(ContainSubstring(".secret.txt"))
+}
+
+func TestRouter_ServeFiles_listing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.Mkdir(filepath.Join(dir, "sub"), 0o755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "sub", "a.txt"), []byte("aaa"), 0o644)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, ".env"), []byte("x"), 0o644)).To(Succeed())
+
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	router.ServeFiles("/files/*filepath", http.Dir(dir)).HideDotfiles()
+	router.ServeFiles("/embedded/*filepath", http.FS(testFS)).DisableListings()
+
+	w := serveStatic(router, http.MethodGet, "/files/sub/a.txt")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("aaa"))
+
+	w = serveStatic(router, http.MethodGet, "/files/")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(ContainSubstring(`<a href="sub/">sub/</a>`))
+	g.Expect(w.Body.String()).NotTo(ContainSubstring(".env"))
+
+	g.Expect(serveStatic(router, http.MethodGet, "/files/.env").Code).To(Equal(http.StatusGone))
+	g.Expect(serveStatic(router, http.MethodGet, "/files/nope").Code).To(Equal(http.StatusGone))
+
+	g.Expect(serveStatic(router, http.MethodGet, "/embedded/css/site.css").Code).To(Equal(http.StatusOK))
+	g.Expect(serveStatic(router, http.MethodGet, "/embedded/images/").Code).To(Equal(http.StatusGone))
+}
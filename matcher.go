@@ -0,0 +1,119 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Matcher is a predicate that a request must satisfy, in addition to matching
+// the method and path, for a route to handle it. See Route.Match.
+type Matcher func(*http.Request) bool
+
+// Match restricts the route to requests that satisfy all of the matchers.
+// The matchers are evaluated after the path has been matched. This allows
+// several routes to be registered for the same method and path, differing
+// only by their matchers; they are tried in the order they were registered.
+//
+// A route without any matchers acts as the default for its method and path.
+// If no route matches, the request is treated as though no route had been
+// registered for its method, so it is answered by the MethodNotAllowed or
+// NotFound handler as appropriate.
+func (rt *Route) Match(matchers ...Matcher) *Route {
+	for _, m := range matchers {
+		if m == nil {
+			panic("matcher must not be nil in path '" + rt.path + "'")
+		}
+	}
+	rt.matchers = append(rt.matchers, matchers...)
+	rt.router.refresh(routeKey{method: rt.method, path: rt.path})
+	return rt
+}
+
+// matches is true if the request satisfies all the route's matchers.
+func (rt *Route) matches(req *http.Request) bool {
+	for _, m := range rt.matchers {
+		if !m(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchable is true if the request satisfies the matchers of at least one of
+// the routes served by the handle. Routes without matchers always count.
+func (r *Router) matchable(m *methodHandle, req *http.Request) bool {
+	if m.route != nil {
+		return true // a single unconditional route
+	}
+	for _, rt := range r.routes[routeKey{method: m.method, path: m.path}] {
+		if rt.matches(req) {
+			return true
+		}
+	}
+	return false
+}
+
+// applicable removes the handles whose routes' matchers all fail for the
+// request, so that their methods are not listed as allowed.
+func (r *Router) applicable(handles methodHandles, req *http.Request) methodHandles {
+	for i := range handles {
+		if !r.matchable(&handles[i], req) {
+			kept := append(methodHandles(nil), handles[:i]...)
+			for j := i + 1; j < len(handles); j++ {
+				if r.matchable(&handles[j], req) {
+					kept = append(kept, handles[j])
+				}
+			}
+			return kept
+		}
+	}
+	return handles
+}
+
+// HeaderEquals matches requests that have a header with the given value.
+func HeaderEquals(name, value string) Matcher {
+	return func(req *http.Request) bool {
+		for _, v := range req.Header.Values(name) {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HeaderMatches matches requests that have a header whose value matches the
+// regular expression.
+func HeaderMatches(name string, re *regexp.Regexp) Matcher {
+	return func(req *http.Request) bool {
+		for _, v := range req.Header.Values(name) {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// QueryPresent matches requests that have the query parameter, with any value.
+func QueryPresent(name string) Matcher {
+	return func(req *http.Request) bool {
+		return req.URL.Query().Has(name)
+	}
+}
+
+// QueryEquals matches requests that have the query parameter with the given value.
+func QueryEquals(name, value string) Matcher {
+	return func(req *http.Request) bool {
+		for _, v := range req.URL.Query()[name] {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
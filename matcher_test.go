@@ -0,0 +1,195 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestRouter_Match(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	handle := func(name string) Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps Params) {
+			saw = name
+		}
+	}
+
+	router := New()
+	router.GET("/report", handle("v2")).Match(HeaderEquals("Accept-Version", "2"))
+	router.GET("/report", handle("v3")).Match(HeaderMatches("Accept-Version", regexp.MustCompile(`^3(\.\d+)?$`)))
+	router.GET("/report", handle("csv")).Match(QueryEquals("format", "csv"))
+	router.GET("/report", handle("debug")).Match(QueryPresent("debug"), func(r *http.Request) bool {
+		return r.RemoteAddr == "192.0.2.1:1234"
+	})
+	router.POST("/report", handle("post"))
+
+	cases := []struct {
+		url, version, expected string
+		code                   int
+	}{
+		{"/report", "2", "v2", http.StatusOK},
+		{"/report", "3.1", "v3", http.StatusOK},
+		{"/report?format=csv", "", "csv", http.StatusOK},
+		{"/report?format=csv", "2", "v2", http.StatusOK},
+		{"/report?debug", "", "debug", http.StatusOK},
+		{"/report?format=xml", "", "", http.StatusMethodNotAllowed},
+		{"/report", "4", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		saw = ""
+		r := httptest.NewRequest(http.MethodGet, c.url, nil)
+		if c.version != "" {
+			r.Header.Set("Accept-Version", c.version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		g.Expect(w.Code).To(Equal(c.code), c.url+" "+c.version)
+		g.Expect(saw).To(Equal(c.expected), c.url+" "+c.version)
+		if c.code == http.StatusMethodNotAllowed {
+			g.Expect(w.Header().Get("Allow")).To(Equal("OPTIONS, POST"))
+		}
+	}
+}
+
+func TestRouter_Match_fallbacks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	router.GET("/items/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = "csv " + ps.ByName("id")
+	}).Match(QueryEquals("format", "csv"))
+
+	// no route matches and no other methods: 404
+	r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+
+	// an unconditional route acts as the default
+	router.GET("/items/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = "default " + ps.ByName("id")
+	})
+
+	r = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("default 1"))
+
+	r = httptest.NewRequest(http.MethodGet, "/items/2?format=csv", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("csv 2"))
+}
+
+func TestRouter_Match_none_observed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	observer := &recordingObserver{}
+	router := New()
+	router.Observer = observer
+	router.GET("/items/:id", noop).Match(QueryEquals("format", "csv"))
+	router.POST("/items/:id", noop)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("Allow")).To(Equal("OPTIONS, POST"))
+
+	g.Expect(observer.ended).To(HaveLen(1))
+	g.Expect(observer.ended[0].Outcome).To(Equal(MethodNotAllowed))
+	g.Expect(observer.ended[0].Pattern).To(BeEmpty())
+}
+
+func TestRouter_Match_none_for_HEAD_via_GET(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/items/:id", noop).Match(QueryEquals("format", "csv"))
+	router.PUT("/items/:id", noop)
+
+	// GET is not allowed, as it would not match either
+	r := httptest.NewRequest(http.MethodHead, "/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("Allow")).To(Equal("OPTIONS, PUT"))
+
+	r = httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Header().Get("Allow")).To(Equal("OPTIONS, PUT"))
+
+	r = httptest.NewRequest(http.MethodHead, "/items/1?format=csv", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+}
+
+func TestRouter_Match_with_Produces(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	handle := func(name string) Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps Params) {
+			saw = name
+		}
+	}
+
+	router := New()
+	router.GET("/x", handle("v2 json")).Match(HeaderEquals("Accept-Version", "2")).Produces("application/json")
+	router.GET("/x", handle("v2 html")).Match(HeaderEquals("Accept-Version", "2")).Produces("text/html")
+	router.GET("/x", handle("v1"))
+
+	r := httptest.NewRequest(http.MethodGet, "/x", nil)
+	r.Header.Set("Accept-Version", "2")
+	r.Header.Set("Accept", "text/html")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("v2 html"))
+
+	r.Header.Del("Accept-Version")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("v1"))
+}
+
+func TestRouter_Match_evaluated_once(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	calls := 0
+	counted := func(r *http.Request) bool {
+		calls++
+		return r.URL.Query().Has("v2")
+	}
+
+	router := New()
+	router.GET("/users/:id", noop).Match(counted)
+	router.GET("/users/:id", noop).Match(QueryPresent("v1"))
+	router.POST("/users/:name", noop)
+
+	r := httptest.NewRequest(http.MethodGet, "/users/1?v2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(calls).To(Equal(1))
+
+	// looked up directly, the handle still selects the route itself
+	calls = 0
+	handle, ps, _ := router.Lookup(http.MethodGet, "/users/1")
+	w = httptest.NewRecorder()
+	handle(w, httptest.NewRequest(http.MethodGet, "/users/1?v2", nil), ps)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(calls).To(Equal(1))
+
+	w = httptest.NewRecorder()
+	handle(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), ps)
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("Allow")).To(Equal("OPTIONS, POST"))
+}
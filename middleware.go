@@ -0,0 +1,65 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware wraps a http.Handler, e.g. to log requests, to alter them or to
+// answer them without calling the next handler.
+type Middleware func(next http.Handler) http.Handler
+
+// Use adds middleware that wraps the whole router, so that it sees every
+// request, including those that are redirected, that are answered with 404
+// or 405, or whose handle panics. The middleware is applied in the order
+// given, so the first middleware is the outermost.
+//
+// Middleware installed this way can learn which route matched a request by
+// using CaptureRoute.
+//
+// Use is not concurrency-safe; it should be called while the routes are
+// being registered.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+
+	var h http.Handler = http.HandlerFunc(r.serve)
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	r.chain = h
+}
+
+// private type used for unique context keying
+type routeCaptureKey struct{}
+
+// CaptureRoute prepares a request so that middleware installed via Router.Use
+// can learn which route matched it. The returned RouteInfo is filled in by the
+// router when a route is matched, before the handle is called; it remains
+// zero (with a blank Pattern) if no route matched.
+//
+//	func(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+//			req, route := httprouter.CaptureRoute(req)
+//			next.ServeHTTP(w, req)
+//			log.Println(route.Pattern)
+//		})
+//	}
+func CaptureRoute(req *http.Request) (*http.Request, *RouteInfo) {
+	info := &RouteInfo{}
+	return req.WithContext(context.WithValue(req.Context(), routeCaptureKey{}, info)), info
+}
+
+// captureRoute records the matched route for CaptureRoute, if required.
+// The parameters are copied because they are recycled after the request.
+func captureRoute(req *http.Request, rt *Route, ps Params) {
+	if info, ok := req.Context().Value(routeCaptureKey{}).(*RouteInfo); ok {
+		*info = rt.info
+		if len(ps) > 0 {
+			info.Params = append(Params(nil), ps...)
+		}
+	}
+}
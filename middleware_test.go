@@ -0,0 +1,93 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_Use_order(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+
+	router := New()
+	router.Use(tag("a"), tag("b"))
+	router.Use(tag("c"))
+	router.GET("/x", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		calls = append(calls, "handle")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	g.Expect(calls).To(Equal([]string{"a", "b", "c", "handle"}))
+}
+
+func TestRouter_Use_sees_unrouted_requests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var patterns []string
+	var statuses []int
+	router := New()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req, route := CaptureRoute(req)
+			sw := NewStatusWriter(w)
+			next.ServeHTTP(sw, req)
+			patterns = append(patterns, route.Pattern)
+			statuses = append(statuses, sw.Status())
+		})
+	})
+	router.GET("/users/:id", func(w http.ResponseWriter, req *http.Request, _ Params) {})
+
+	for _, r := range []struct{ method, path string }{
+		{http.MethodGet, "/users/1"},
+		{http.MethodPost, "/users/1"},
+		{http.MethodGet, "/nope"},
+		{http.MethodGet, "/users/1/"},
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(r.method, r.path, nil))
+	}
+
+	g.Expect(patterns).To(Equal([]string{"/users/:id", "", "", ""}))
+	g.Expect(statuses).To(Equal([]int{0, http.StatusMethodNotAllowed, http.StatusNotFound, http.StatusMovedPermanently}))
+}
+
+func TestCaptureRoute_variants(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var captured *RouteInfo
+	router := New()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req, captured = CaptureRoute(req)
+			next.ServeHTTP(w, req)
+		})
+	})
+	router.GET("/doc/:id", func(w http.ResponseWriter, req *http.Request, _ Params) {}).Produces("text/html").Named("html")
+	router.GET("/doc/:id", func(w http.ResponseWriter, req *http.Request, _ Params) {}).Produces("application/json").Named("json")
+
+	req := httptest.NewRequest(http.MethodGet, "/doc/9", nil)
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	g.Expect(captured).To(Equal(&RouteInfo{
+		Method:  http.MethodGet,
+		Pattern: "/doc/:id",
+		Name:    "json",
+		Params:  Params{{"id", "9"}},
+	}))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// mount records a router that has been mounted, so that its handlers apply
+// to unrouted requests under its prefix.
+type mount struct {
+	prefix string
+	router *Router
+}
+
+// Mount adds all the routes of the child router to this router, with the
+// prefix prepended to their paths, e.g.
+//
+//	admin := httprouter.New()
+//	admin.GET("/users/:id", showUser).Named("admin-user")
+//	router.Mount("/admin", admin)
+//	// GET /admin/users/42 is served by showUser
+//
+// Unlike SubRouter, the child's routes become part of this router. So they
+// are included in ListPaths and Routes, they share the Allow headers of 405
+// and OPTIONS replies with this router's routes, and they can be found by
+// name using NamedRoute and Reverse. The request paths and the patterns seen
+// by the handlers, e.g. via RouteFromContext, include the prefix.
+//
+// The child's NotFound and MethodNotAllowed handlers, if set, are used for
+// requests under the prefix that match no route. Those of a child mounted at
+// the root, i.e. with the prefix "" or "/", apply to every path that is not
+// under the prefix of a more deeply mounted router. The child's middleware, if
+// any, wraps each of its routes. Otherwise, this router's settings apply,
+// including its hooks, Observer, PanicHandler and the handlers used for
+// timeouts and limits.
+//
+// The child's routes are moved, not copied, so the child must not be used
+// to serve requests after it has been mounted. Routes registered with the
+// child later are not mounted, but its existing routes can still be refined.
+// CONNECT routes are not mounted. If any of the child's routes conflict with
+// this router's routes, Mount panics with a *ConflictError and neither router
+// is altered.
+func (r *Router) Mount(prefix string, child *Router) {
+	if child == r {
+		panic("a router cannot be mounted on itself")
+	}
+	if prefix != "" && prefix[0] != '/' {
+		panic("prefix must begin with '/' in prefix '" + prefix + "'")
+	}
+	if strings.ContainsAny(prefix, ":*") {
+		panic("prefix must not contain wildcards in prefix '" + prefix + "'")
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	routes := child.Routes()
+
+	// the routes are checked first, so that neither router is altered if any
+	// of them conflict
+	specs := make([]RouteSpec, 0, len(routes))
+	seen := make(map[routeKey]bool, len(routes))
+	for _, rt := range routes {
+		if key := (routeKey{method: rt.method, path: prefix + rt.path}); !seen[key] {
+			seen[key] = true
+			specs = append(specs, RouteSpec{Method: key.method, Path: key.path})
+		}
+	}
+	if errs := r.Validate(specs...); len(errs) > 0 {
+		panic(errs[0])
+	}
+
+	varsCount := uint16(0)
+	if child.SaveMatchedRoutePath {
+		varsCount++
+	}
+
+	for _, rt := range routes {
+		rt.path = prefix + rt.path
+		rt.info.Pattern = rt.path
+		rt.router = r
+		if len(child.middleware) > 0 {
+			rt.handle = child.mounted(rt.handle)
+		}
+		r.add(rt, varsCount)
+	}
+
+	r.mounts = append(r.mounts, mount{prefix: prefix, router: child})
+	for _, m := range child.mounts {
+		r.mounts = append(r.mounts, mount{prefix: prefix + m.prefix, router: m.router})
+	}
+}
+
+// private type used for unique context keying
+type mountedParamsKey struct{}
+
+// mounted wraps a handle of a mounted router in that router's middleware. The
+// parameters are passed through the middleware in the request context.
+func (r *Router) mounted(handle Handle) Handle {
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ps, _ := req.Context().Value(mountedParamsKey{}).(Params)
+		handle(w, req, ps)
+	})
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), mountedParamsKey{}, ps)))
+	}
+}
+
+// mountedAt gets the most deeply mounted router whose prefix contains the
+// path and for which the handler is set, or nil if there is none.
+func (r *Router) mountedAt(path string, handler func(*Router) http.Handler) *Router {
+	var found *Router
+	longest := -1 // a router mounted at the root has a blank prefix
+	for _, m := range r.mounts {
+		if len(m.prefix) > longest && handler(m.router) != nil && underPrefix(path, m.prefix) {
+			found, longest = m.router, len(m.prefix)
+		}
+	}
+	return found
+}
+
+func underPrefix(path, prefix string) bool {
+	return strings.HasPrefix(path, prefix) && (len(path) == len(prefix) || path[len(prefix)] == '/')
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func statusHandler(code int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(code)
+	})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var user string
+	var info *RouteInfo
+
+	child := New()
+	child.GET("/users/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		user = ps.ByName("id")
+	}).Named("user")
+	child.HandlerFunc(http.MethodPost, "/users", func(w http.ResponseWriter, req *http.Request) {
+		info = RouteFromContext(req.Context())
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	router := New()
+	router.GET("/api/users", noop)
+	router.Mount("/api/", child)
+
+	w := serveStatic(router, http.MethodGet, "/api/users/42")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(user).To(Equal("42"))
+
+	w = serveStatic(router, http.MethodPost, "/api/users")
+	g.Expect(w.Code).To(Equal(http.StatusCreated))
+	g.Expect(info.Pattern).To(Equal("/api/users"))
+
+	g.Expect(router.ListPaths("")).To(Equal(map[string][]string{
+		http.MethodGet:  {"/api/users", "/api/users/:id"},
+		http.MethodPost: {"/api/users"},
+	}))
+	g.Expect(router.Routes()).To(HaveLen(3))
+
+	w = serveStatic(router, http.MethodOptions, "/api/users")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Allow")).To(Equal("GET, OPTIONS, POST"))
+
+	w = serveStatic(router, http.MethodDelete, "/api/users")
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("Allow")).To(Equal("GET, OPTIONS, POST"))
+
+	path, err := router.Reverse("user", Params{{Key: "id", Value: "7"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal("/api/users/7"))
+}
+
+func TestRouter_Mount_handlers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	grandchild := New()
+	grandchild.NotFound = statusHandler(http.StatusTeapot)
+	grandchild.GET("/x", noop)
+
+	child := New()
+	child.NotFound = statusHandler(http.StatusGone)
+	child.MethodNotAllowed = statusHandler(http.StatusConflict)
+	child.GET("/a", noop)
+	child.Mount("/deeper", grandchild)
+
+	router := New()
+	router.NotFound = statusHandler(http.StatusNotImplemented)
+	router.GET("/a", noop)
+	router.Mount("/child", child)
+
+	cases := []struct {
+		method, path string
+		code         int
+	}{
+		{method: http.MethodGet, path: "/child/a", code: http.StatusOK},
+		{method: http.MethodGet, path: "/child/deeper/x", code: http.StatusOK},
+		{method: http.MethodGet, path: "/child/nope", code: http.StatusGone},
+		{method: http.MethodGet, path: "/child", code: http.StatusGone},
+		{method: http.MethodGet, path: "/children", code: http.StatusNotImplemented},
+		{method: http.MethodGet, path: "/child/deeper/nope", code: http.StatusTeapot},
+		{method: http.MethodGet, path: "/nope", code: http.StatusNotImplemented},
+		{method: http.MethodPut, path: "/child/a", code: http.StatusConflict},
+		{method: http.MethodPut, path: "/a", code: http.StatusMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		w := serveStatic(router, c.method, c.path)
+		g.Expect(w.Code).To(Equal(c.code), c.method+" "+c.path)
+	}
+}
+
+func TestRouter_Mount_versionedByHeader(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the child has neither an Observer nor a NotFound handler of its own
+	child := New()
+	api := child.Versions("1", "2").ByHeader("Accept-Version")
+	api.GET("/orders/:id", noop, "1")
+
+	observer := &recordingObserver{}
+	router := New()
+	router.Observer = observer
+	router.NotFound = statusHandler(http.StatusTeapot)
+	router.Mount("/api", child)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/orders/1", nil)
+	r.Header.Set("Accept-Version", "3")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	g.Expect(w.Code).To(Equal(http.StatusTeapot))
+	g.Expect(observer.ended).To(HaveLen(1))
+	g.Expect(observer.ended[0].Pattern).To(Equal("/api/orders/:id"))
+	g.Expect(observer.ended[0].Outcome).To(Equal(NotFound))
+}
+
+func TestRouter_Mount_middleware(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var id string
+
+	child := New()
+	child.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("X-Child", "yes")
+			next.ServeHTTP(w, req)
+		})
+	})
+	child.GET("/items/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		id = ps.ByName("id")
+	})
+
+	router := New()
+	router.GET("/other", noop)
+	router.Mount("/shop", child)
+
+	w := serveStatic(router, http.MethodGet, "/shop/items/9")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("X-Child")).To(Equal("yes"))
+	g.Expect(id).To(Equal("9"))
+
+	w = serveStatic(router, http.MethodGet, "/other")
+	g.Expect(w.Header().Get("X-Child")).To(BeEmpty())
+}
+
+func TestRouter_Mount_static(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	child := New()
+	child.NotFound = statusHandler(http.StatusGone)
+	child.ServeFS("/static/*", testFS).Route().Named("static")
+
+	router := New()
+	router.Mount("/site", child)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/site/static/css/site.css", nil))
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("body{}"))
+
+	g.Expect(serveStatic(router, http.MethodGet, "/site/static/nope.css").Code).To(Equal(http.StatusGone))
+
+	path, err := router.Reverse("static", Params{{Key: "filepath", Value: "/css/site.css"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal("/site/static/css/site.css"))
+}
+
+func TestRouter_Mount_panics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	g.Expect(func() { router.Mount("api", New()) }).To(Panic())
+	g.Expect(func() { router.Mount("/:api", New()) }).To(Panic())
+	g.Expect(func() { router.Mount("/api", router) }).To(Panic())
+
+	router.GET("/api/:id", noop)
+	child := New()
+	child.GET("/:name", noop)
+	g.Expect(func() { router.Mount("/api", child) }).To(PanicWith(BeAssignableToTypeOf(&ConflictError{})))
+
+	// neither router is altered
+	g.Expect(child.Routes()[0].Path()).To(Equal("/:name"))
+	g.Expect(child.Routes()[0].router).To(BeIdenticalTo(child))
+	g.Expect(router.Routes()).To(HaveLen(1))
+	g.Expect(router.mounts).To(BeEmpty())
+}
+
+func TestRouter_Mount_root(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, prefix := range []string{"", "/"} {
+		child := New()
+		child.NotFound = statusHandler(http.StatusTeapot)
+		child.GET("/users", noop)
+		deeper := New()
+		deeper.NotFound = statusHandler(http.StatusGone)
+
+		router := New()
+		router.Mount(prefix, child)
+		router.Mount("/deeper", deeper)
+
+		g.Expect(serveStatic(router, http.MethodGet, "/users").Code).To(Equal(http.StatusOK), prefix)
+		g.Expect(serveStatic(router, http.MethodGet, "/nope").Code).To(Equal(http.StatusTeapot), prefix)
+		g.Expect(serveStatic(router, http.MethodGet, "/deeper/nope").Code).To(Equal(http.StatusGone), prefix)
+	}
+}
+
+func TestRouter_Mount_SaveMatchedRoutePath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var matched []string
+	child := New()
+	child.SaveMatchedRoutePath = true
+	child.GET("/users/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		matched = append(matched, ps.MatchedRoutePath())
+	})
+	child.GET("/users", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		matched = append(matched, ps.MatchedRoutePath())
+	})
+
+	router := New()
+	router.Mount("/api", child)
+
+	serveStatic(router, http.MethodGet, "/api/users/1")
+	serveStatic(router, http.MethodGet, "/api/users")
+	g.Expect(matched).To(Equal([]string{"/api/users/:id", "/api/users"}))
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Produces restricts the route to requests that accept at least one of the
+// given media types, e.g. "application/json". The Accept request header is
+// used to select between routes registered for the same method and path,
+// taking into account the q-value ranking. If no route is acceptable, the
+// Router's NotAcceptable handler is used.
+//
+// A route without any media types acts as the default for its method and
+// path, and is used when no other route matches the Accept header.
+func (rt *Route) Produces(mediaTypes ...string) *Route {
+	for _, mt := range mediaTypes {
+		if strings.IndexByte(mt, '/') <= 0 {
+			panic("'" + mt + "' is not a valid media type in path '" + rt.path + "'")
+		}
+		rt.produces = append(rt.produces, strings.ToLower(mt))
+	}
+	rt.router.refresh(routeKey{method: rt.method, path: rt.path})
+	return rt
+}
+
+func (r *Router) notAcceptable(w http.ResponseWriter, req *http.Request) {
+	r.observe(w, req, "", NotAcceptable)
+	if r.NotAcceptable != nil {
+		r.NotAcceptable.ServeHTTP(w, req)
+	} else {
+		http.Error(w,
+			http.StatusText(http.StatusNotAcceptable),
+			http.StatusNotAcceptable,
+		)
+	}
+}
+
+// mediaRange is one of the ranges listed in an Accept header.
+type mediaRange struct {
+	value string // e.g. "text/html", "text/*" or "*/*"
+	q     float64
+}
+
+// parseAccept parses the values of Accept headers, ignoring any parameters
+// other than q.
+func parseAccept(values []string) []mediaRange {
+	var ranges []mediaRange
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			params := strings.Split(part, ";")
+			mr := mediaRange{value: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+			if mr.value == "" {
+				continue
+			}
+			for _, p := range params[1:] {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "q=") || strings.HasPrefix(p, "Q=") {
+					if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+						mr.q = q
+					}
+				}
+			}
+			ranges = append(ranges, mr)
+		}
+	}
+	return ranges
+}
+
+// quality finds the q-value of the most specific media range that matches the
+// media type, or zero if none matches.
+func quality(mediaType string, ranges []mediaRange) float64 {
+	q, specificity := 0.0, -1
+	for _, mr := range ranges {
+		s := -1
+		switch {
+		case mr.value == mediaType:
+			s = 2
+		case mr.value == "*/*":
+			s = 0
+		case strings.HasSuffix(mr.value, "/*") && strings.HasPrefix(mediaType, mr.value[:len(mr.value)-1]):
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = mr.q, s
+		}
+	}
+	return q
+}
+
+// selectByAccept picks the route whose media types best match the Accept
+// header values. Earlier routes win ties. Routes without media types are only
+// chosen if none of the others is acceptable. If the request has no Accept
+// header, the first route is chosen.
+func selectByAccept(routes []*Route, accept []string) *Route {
+	if len(accept) == 0 {
+		return routes[0]
+	}
+
+	ranges := parseAccept(accept)
+
+	var best, fallback *Route
+	bestQ := 0.0
+	for _, rt := range routes {
+		if len(rt.produces) == 0 {
+			if fallback == nil {
+				fallback = rt
+			}
+			continue
+		}
+		for _, mt := range rt.produces {
+			if q := quality(mt, ranges); q > bestQ {
+				best, bestQ = rt, q
+			}
+		}
+	}
+
+	if best != nil {
+		return best
+	}
+	return fallback
+}
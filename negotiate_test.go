@@ -0,0 +1,118 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_Produces(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	handle := func(name string) Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps Params) {
+			saw = name + " " + ps.ByName("id")
+		}
+	}
+
+	router := New()
+	router.GET("/items/:id", handle("json")).Produces("application/json")
+	router.GET("/items/:id", handle("html")).Produces("text/html", "application/xhtml+xml")
+
+	cases := []struct {
+		accept, expected string
+		code             int
+	}{
+		{"", "json 1", http.StatusOK},
+		{"application/json", "json 1", http.StatusOK},
+		{"text/html", "html 1", http.StatusOK},
+		{"text/html;q=0.5, application/json;q=0.9", "json 1", http.StatusOK},
+		{"text/html, application/json;q=0.9", "html 1", http.StatusOK},
+		{"text/*, application/json;q=0.1", "html 1", http.StatusOK},
+		{"*/*", "json 1", http.StatusOK},
+		{"*/*;q=0.1, application/xhtml+xml", "html 1", http.StatusOK},
+		{"application/*;q=0, text/html;q=0.2", "html 1", http.StatusOK},
+		{"image/png", "", http.StatusNotAcceptable},
+		{"application/json;q=0", "", http.StatusNotAcceptable},
+	}
+
+	for _, c := range cases {
+		saw = ""
+		r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		if c.accept != "" {
+			r.Header.Set("Accept", c.accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		g.Expect(w.Code).To(Equal(c.code), c.accept)
+		g.Expect(saw).To(Equal(c.expected), c.accept)
+		g.Expect(w.Header().Get("Vary")).To(Equal("Accept"), c.accept)
+	}
+}
+
+func TestRouter_Produces_with_default_route(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	router.GET("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = "json"
+	}).Produces("application/json")
+	router.GET("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = "default"
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	r.Header.Set("Accept", "image/png")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("default"))
+
+	r.Header.Set("Accept", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal("json"))
+
+	// a further unconditional route is a duplicate
+	recv := catchPanic(func() {
+		router.GET("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {})
+	})
+	g.Expect(recv).NotTo(BeNil())
+}
+
+func TestRouter_NotAcceptable_custom_handler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.NotAcceptable = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.GET("/src/*filepath", func(w http.ResponseWriter, r *http.Request, ps Params) {}).Produces("text/plain")
+
+	r := httptest.NewRequest(http.MethodGet, "/src/a/b", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusTeapot))
+}
+
+func TestRouter_Produces_duplicate_unconditional_route(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {})
+
+	recv := catchPanic(func() {
+		router.GET("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {}).Produces("text/html")
+	})
+	g.Expect(recv).NotTo(BeNil())
+
+	recv = catchPanic(func() {
+		router.POST("/items", func(w http.ResponseWriter, r *http.Request, ps Params) {}).Produces("html")
+	})
+	g.Expect(recv).NotTo(BeNil())
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"time"
+)
+
+// Outcome describes how the router dispatched a request.
+type Outcome uint8
+
+const (
+	// Handled means that the request was passed to the handle of a route.
+	Handled Outcome = iota
+	// Redirected means that the router redirected the request to a
+	// corrected path.
+	Redirected
+	// AutoOPTIONS means that the router replied automatically to an
+	// OPTIONS request.
+	AutoOPTIONS
+	// MethodNotAllowed means that the path matched a route but not for
+	// the request method.
+	MethodNotAllowed
+	// NotAcceptable means that the path matched a route but not for any
+	// media type accepted by the client.
+	NotAcceptable
+	// NotFound means that no route matched the request path, nor did any of
+	// the handlers given to Router.Fallback accept the request.
+	NotFound
+	// FellBack means that no route matched the request path, and one of the
+	// handlers given to Router.Fallback answered it other than with 404.
+	FellBack
+)
+
+var outcomeNames = [...]string{"handled", "redirected", "options", "method_not_allowed", "not_acceptable", "not_found", "fallback"}
+
+// String gets a short lowercase name for the outcome, e.g. "not_found".
+func (o Outcome) String() string {
+	if int(o) < len(outcomeNames) {
+		return outcomeNames[o]
+	}
+	return "unknown"
+}
+
+// RequestEvent describes a request that has been dispatched by the router.
+type RequestEvent struct {
+	// Method is the request method.
+	Method string
+	// Pattern is the path pattern of the route that matched, e.g.
+	// "/users/:id". It is blank if no route matched.
+	Pattern string
+	// Outcome is how the router dispatched the request.
+	Outcome Outcome
+	// Status is the response status code. This is only known at the end.
+	Status int
+	// Size is the number of bytes in the response body. This is only
+	// known at the end.
+	Size int64
+	// Start is when the router received the request.
+	Start time.Time
+	// Duration is how long the request took. This is only known at the end.
+	Duration time.Duration
+
+	registered bool // routes are registered for the method
+}
+
+// Observer receives notifications about each request, e.g. for gathering
+// metrics. Route patterns are provided so that metrics can be labelled
+// without the excessive cardinality that raw request paths would cause.
+//
+// Observers must be safe for concurrent use.
+type Observer interface {
+	// OnRequestStart is called once the router has decided how the request
+	// will be dispatched, before the response is written.
+	OnRequestStart(req *http.Request, ev RequestEvent)
+	// OnRequestEnd is called once the response is complete, even if the
+	// handle panicked.
+	OnRequestEnd(req *http.Request, ev RequestEvent)
+}
+
+// observedWriter captures the response status and size for an Observer,
+// and also how the request was dispatched.
+type observedWriter struct {
+	*StatusWriter
+	observer Observer
+	methods  []string // those registered with the router
+	event    RequestEvent
+	started  bool
+}
+
+func newObservedWriter(w http.ResponseWriter, req *http.Request, observer Observer, methods []string) *observedWriter {
+	return &observedWriter{
+		StatusWriter: &StatusWriter{ResponseWriter: w},
+		observer:     observer,
+		methods:      methods,
+		event:        RequestEvent{Method: req.Method, Start: time.Now()},
+	}
+}
+
+// observedWriterKey is the context key for the observedWriter, by which it is
+// found if the writer has been wrapped, e.g. by the limits of a route.
+type observedWriterKey struct{}
+
+// observe records how the request is being dispatched, if it is being
+// observed. The first call notifies the observer that the request has started;
+// subsequent calls only alter the outcome.
+func (r *Router) observe(w http.ResponseWriter, req *http.Request, pattern string, outcome Outcome) {
+	if r.Observer == nil {
+		return
+	}
+
+	ow, ok := w.(*observedWriter)
+	if !ok {
+		if ow, ok = req.Context().Value(observedWriterKey{}).(*observedWriter); !ok {
+			return
+		}
+	}
+
+	ow.event.Outcome = outcome
+	if !ow.started {
+		ow.start(req, pattern)
+	}
+}
+
+func (w *observedWriter) start(req *http.Request, pattern string) {
+	w.started = true
+	w.event.Method = req.Method // after any override
+	w.event.registered = contains(w.methods, req.Method)
+	w.event.Pattern = pattern
+	w.observer.OnRequestStart(req, w.event)
+}
+
+func (w *observedWriter) end(req *http.Request) {
+	if !w.started {
+		w.start(req, "")
+	}
+
+	w.event.Status = w.status
+	if w.event.Status == 0 {
+		w.event.Status = http.StatusOK
+	}
+	w.event.Size = w.size
+	w.event.Duration = time.Since(w.event.Start)
+	w.observer.OnRequestEnd(req, w.event)
+}
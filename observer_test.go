@@ -0,0 +1,105 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	started []RequestEvent
+	ended   []RequestEvent
+}
+
+func (o *recordingObserver) OnRequestStart(_ *http.Request, ev RequestEvent) {
+	o.started = append(o.started, ev)
+}
+
+func (o *recordingObserver) OnRequestEnd(_ *http.Request, ev RequestEvent) {
+	o.ended = append(o.ended, ev)
+}
+
+func TestRouter_Observer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	observer := &recordingObserver{}
+
+	router := New()
+	router.Observer = observer
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	router.GET("/json", func(w http.ResponseWriter, r *http.Request, ps Params) {}).Produces("application/json")
+
+	cases := []struct {
+		method, url, pattern string
+		outcome              Outcome
+		status               int
+		size                 int64
+	}{
+		{http.MethodGet, "/users/1", "/users/:id", Handled, http.StatusCreated, 5},
+		{http.MethodHead, "/users/1", "/users/:id", Handled, http.StatusCreated, 5},
+		{http.MethodGet, "/users/1/", "", Redirected, http.StatusMovedPermanently, 43},
+		{http.MethodPut, "/users/1", "", MethodNotAllowed, http.StatusMethodNotAllowed, 19},
+		{http.MethodOptions, "/users/1", "", AutoOPTIONS, http.StatusOK, 0},
+		{http.MethodGet, "/nope", "", NotFound, http.StatusNotFound, 19},
+		{http.MethodGet, "/json", "/json", NotAcceptable, http.StatusNotAcceptable, 15},
+	}
+
+	for i, c := range cases {
+		r := httptest.NewRequest(c.method, c.url, nil)
+		r.Header.Set("Accept", "text/html")
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		g.Expect(observer.started).To(HaveLen(i+1), c.url)
+		g.Expect(observer.ended).To(HaveLen(i+1), c.url)
+
+		start := observer.started[i]
+		g.Expect(start.Method).To(Equal(c.method), c.url)
+		g.Expect(start.Pattern).To(Equal(c.pattern), c.url)
+
+		end := observer.ended[i]
+		g.Expect(end.Method).To(Equal(c.method), c.url)
+		g.Expect(end.Pattern).To(Equal(c.pattern), c.url)
+		g.Expect(end.Outcome).To(Equal(c.outcome), c.url)
+		g.Expect(end.Status).To(Equal(c.status), c.url)
+		g.Expect(end.Size).To(Equal(c.size), c.url)
+		g.Expect(end.Duration).To(BeNumerically(">", 0), c.url)
+	}
+}
+
+func TestRouter_Observer_wrappedWriter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	observer := &recordingObserver{}
+
+	router := New()
+	router.Observer = observer
+	api := router.Versions("1", "2").ByHeader("Accept-Version")
+	api.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {}, "1").
+		Routes()[0].WithTimeout(time.Second)
+
+	// the route's timeout wraps the writer before the unknown version is rejected
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("Accept-Version", "3")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	g.Expect(observer.ended).To(HaveLen(1))
+	g.Expect(observer.ended[0].Pattern).To(Equal("/users/:id"))
+	g.Expect(observer.ended[0].Outcome).To(Equal(NotFound))
+	g.Expect(observer.ended[0].Status).To(Equal(http.StatusNotFound))
+}
+
+func TestOutcome_String(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(Handled.String()).To(Equal("handled"))
+	g.Expect(NotFound.String()).To(Equal("not_found"))
+	g.Expect(Outcome(99).String()).To(Equal("unknown"))
+}
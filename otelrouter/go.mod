@@ -0,0 +1,28 @@
+module github.com/rickb777/httprouter/v3/otelrouter
+
+go 1.22
+
+require (
+	github.com/onsi/gomega v1.35.0
+	github.com/rickb777/httprouter/v3 v3.0.0-20261018171956-7dc864064877
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/sdk v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.31.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The replace directive is only for local development. It is ignored where
+// this module is required, so the requirement above must be a version of
+// httprouter that has OnMatch and RouteInfo.
+replace github.com/rickb777/httprouter/v3 => ../
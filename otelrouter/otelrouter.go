@@ -0,0 +1,87 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+// Package otelrouter integrates httprouter with OpenTelemetry tracing, so that
+// server spans are named by route pattern, e.g. "GET /users/:id", rather than
+// by the raw request path.
+//
+// A typical setup is:
+//
+//	router := httprouter.New()
+//	otelrouter.Install(router)
+//	// ... add routes
+//	handler := otelrouter.Middleware(otel.Tracer("my-service"), router)
+//	log.Fatal(http.ListenAndServe(":8080", handler))
+//
+// Install can also be used alone if spans are started by other middleware,
+// such as otelhttp.
+//
+// This package is a separate module, so that httprouter itself does not
+// depend on OpenTelemetry.
+package otelrouter
+
+import (
+	"net/http"
+
+	"github.com/rickb777/httprouter/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RouteKey is the attribute key for the matched route pattern,
+// following the OpenTelemetry semantic conventions.
+const RouteKey = attribute.Key("http.route")
+
+// Install sets the router's OnMatch hook so that the span in each request's
+// context is renamed after the matched route. Any existing hook is still
+// called.
+func Install(router *httprouter.Router) {
+	existing := router.OnMatch
+	router.OnMatch = func(req *http.Request, info *httprouter.RouteInfo) {
+		RenameSpan(req, info)
+		if existing != nil {
+			existing(req, info)
+		}
+	}
+}
+
+// RenameSpan renames the span in the request context as "METHOD pattern" and
+// sets the http.route attribute. It does nothing if there is no recording span.
+func RenameSpan(req *http.Request, info *httprouter.RouteInfo) {
+	span := trace.SpanFromContext(req.Context())
+	if !span.IsRecording() {
+		return
+	}
+	span.SetName(req.Method + " " + info.Pattern)
+	span.SetAttributes(RouteKey.String(info.Pattern))
+}
+
+// Middleware starts a server span for each request, using the tracer. The span
+// is initially named by the request method only; Install must be used on the
+// router for the span to be renamed after the matched route.
+func Middleware(tracer trace.Tracer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, span := tracer.Start(req.Context(), req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+			),
+		)
+		defer span.End()
+
+		sw := httprouter.NewStatusWriter(w)
+		next.ServeHTTP(sw, req.WithContext(ctx))
+
+		status := sw.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
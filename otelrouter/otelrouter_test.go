@@ -0,0 +1,59 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package otelrouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/httprouter/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware_names_spans_by_route(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	hooked := 0
+	router := httprouter.New()
+	router.OnMatch = func(req *http.Request, info *httprouter.RouteInfo) {
+		hooked++
+	}
+	Install(router)
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {})
+	router.GET("/boom", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	handler := Middleware(tracer, router)
+
+	for _, url := range []string{"/users/123", "/boom", "/nope"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(3))
+
+	g.Expect(spans[0].Name).To(Equal("GET /users/:id"))
+	g.Expect(spans[0].Attributes).To(ContainElement(RouteKey.String("/users/:id")))
+	g.Expect(spans[0].Attributes).To(ContainElement(attribute.Int("http.response.status_code", 200)))
+
+	g.Expect(spans[1].Name).To(Equal("GET /boom"))
+	g.Expect(spans[1].Status.Code).To(Equal(codes.Error))
+
+	// unmatched requests keep the generic name
+	g.Expect(spans[2].Name).To(Equal("GET"))
+	g.Expect(spans[2].Attributes).To(ContainElement(attribute.Int("http.response.status_code", 404)))
+
+	g.Expect(hooked).To(Equal(2))
+}
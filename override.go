@@ -0,0 +1,94 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MethodOverrideHeader is the request header that can override the method of
+// a POST request, if Router.MethodOverrides is set.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// MethodOverrideField is the form field that can override the method of
+// a POST request, if Router.MethodOverrides is set. It is only looked for in
+// application/x-www-form-urlencoded bodies of up to MaxMethodOverrideForm
+// bytes; other requests need MethodOverrideHeader.
+const MethodOverrideField = "_method"
+
+// MaxMethodOverrideForm is the largest form body that is read to find
+// MethodOverrideField. The body is read before routing, so before any limit
+// set by WithMaxBodySize applies.
+const MaxMethodOverrideForm = 8 << 10
+
+// overrideMethod returns the request, modified if its method has been
+// overridden by a header or a form field.
+func (r *Router) overrideMethod(req *http.Request) *http.Request {
+	method := req.Header.Get(MethodOverrideHeader)
+
+	if method == "" && isFormContent(req) {
+		method, req = overrideField(req)
+	}
+
+	if method == "" {
+		return req
+	}
+
+	method = strings.ToUpper(method)
+	if !contains(r.MethodOverrides, method) {
+		return req
+	}
+
+	// make a copy so that the original is unaltered
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.Method = method
+	return r2
+}
+
+// overrideField reads the override from a form body. The body is read only
+// up to MaxMethodOverrideForm, and the request is returned with a copy of the
+// body that can be read again by the handler. The original request is
+// unaltered.
+func overrideField(req *http.Request) (string, *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", req
+	}
+
+	// one byte more than the limit shows whether it is exceeded; unlike
+	// http.MaxBytesReader, io.LimitReader keeps every byte it reads
+	body, err := io.ReadAll(io.LimitReader(req.Body, MaxMethodOverrideForm+1))
+
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+
+	if err != nil || len(body) > MaxMethodOverrideForm {
+		return "", r2 // too large to look for the field
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", r2
+	}
+	return form.Get(MethodOverrideField), r2
+}
+
+// replayBody is a request body that supplies the part that has already been
+// read again, followed by the rest.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+func isFormContent(req *http.Request) bool {
+	ct, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return ct == "application/x-www-form-urlencoded"
+}
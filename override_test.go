@@ -0,0 +1,127 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_MethodOverrides(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw []string
+	handle := func(w http.ResponseWriter, r *http.Request, _ Params) {
+		saw = append(saw, r.Method)
+	}
+
+	router := New()
+	router.MethodOverrides = []string{http.MethodPut, http.MethodDelete}
+	router.POST("/item", handle)
+	router.PUT("/item", handle)
+	router.DELETE("/item", handle)
+	router.PATCH("/item", handle)
+
+	// by header
+	r := httptest.NewRequest(http.MethodPost, "/item", nil)
+	r.Header.Set(MethodOverrideHeader, "put")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(r.Method).To(Equal(http.MethodPost))
+
+	// by form field
+	r = httptest.NewRequest(http.MethodPost, "/item", strings.NewReader("_method=DELETE&x=1"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	// not in the allow-list
+	r = httptest.NewRequest(http.MethodPost, "/item", nil)
+	r.Header.Set(MethodOverrideHeader, http.MethodPatch)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	// only POST can be overridden
+	r = httptest.NewRequest(http.MethodGet, "/item", nil)
+	r.Header.Set(MethodOverrideHeader, http.MethodPut)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+
+	g.Expect(saw).To(Equal([]string{http.MethodPut, http.MethodDelete, http.MethodPost}))
+}
+
+func TestRouter_MethodOverrides_disabled_by_default(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	router.POST("/item", func(w http.ResponseWriter, r *http.Request, _ Params) {
+		saw = r.Method
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/item", nil)
+	r.Header.Set(MethodOverrideHeader, http.MethodPut)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(saw).To(Equal(http.MethodPost))
+}
+
+func TestRouter_MethodOverrides_form_body(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var method, body string
+	handle := func(w http.ResponseWriter, r *http.Request, _ Params) {
+		method = r.Method
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}
+
+	router := New()
+	router.MethodOverrides = []string{http.MethodDelete}
+	router.POST("/item", handle)
+	router.DELETE("/item", handle)
+
+	// the handler can still read the form
+	r := httptest.NewRequest(http.MethodPost, "/item", strings.NewReader("_method=DELETE&x=1"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(method).To(Equal(http.MethodDelete))
+	g.Expect(body).To(Equal("_method=DELETE&x=1"))
+
+	// larger forms are not read
+	large := "x=" + strings.Repeat("a", MaxMethodOverrideForm) + "&_method=DELETE"
+	r = httptest.NewRequest(http.MethodPost, "/item", strings.NewReader(large))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(method).To(Equal(http.MethodPost))
+	g.Expect(body).To(Equal(large))
+
+	// nor are multipart bodies
+	multipart := "--b\r\nContent-Disposition: form-data; name=\"_method\"\r\n\r\nDELETE\r\n--b--\r\n"
+	r = httptest.NewRequest(http.MethodPost, "/item", strings.NewReader(multipart))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	g.Expect(method).To(Equal(http.MethodPost))
+	g.Expect(body).To(Equal(multipart))
+}
+
+func TestRouter_MethodOverrides_observed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	observer := &recordingObserver{}
+	router := New()
+	router.Observer = observer
+	router.MethodOverrides = []string{http.MethodPut}
+	router.PUT("/item", func(w http.ResponseWriter, r *http.Request, _ Params) {})
+
+	r := httptest.NewRequest(http.MethodPost, "/item", nil)
+	r.Header.Set(MethodOverrideHeader, http.MethodPut)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	g.Expect(observer.started).To(HaveLen(1))
+	g.Expect(observer.started[0].Method).To(Equal(http.MethodPut))
+	g.Expect(observer.ended[0].Method).To(Equal(http.MethodPut))
+}
@@ -64,6 +64,10 @@ var MatchedRoutePathParam = "$matchedRoutePath"
 // MatchedRoutePath retrieves the path of the matched route.
 // Router.SaveMatchedRoutePath must have been enabled when the respective
 // handler was added, otherwise this function always returns an empty string.
+//
+// Deprecated: use RouteFromContext instead. For routes registered with a
+// Handle function, it needs Router.SaveRouteInfo to be enabled, which
+// applies to all routes, whenever they were registered.
 func (ps Params) MatchedRoutePath() string {
 	return ps.ByName(MatchedRoutePathParam)
 }
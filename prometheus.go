@@ -0,0 +1,242 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the buckets used
+// by NewPrometheusObserver if none are specified.
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusObserver is an Observer that gathers request metrics. It is also
+// a http.Handler that exposes the metrics in the Prometheus text format, so it
+// can be registered as a route, e.g.
+//
+//	metrics := httprouter.NewPrometheusObserver()
+//	router.Observer = metrics
+//	router.Handler(http.MethodGet, "/metrics", metrics)
+//
+// The metrics are labelled by route pattern rather than by request path.
+// Requests that match no route have a blank route label. Likewise, requests
+// with methods that are neither listed in AllMethods nor registered with the
+// router have the method label "other", so that clients cannot create
+// arbitrarily many series.
+type PrometheusObserver struct {
+	// Prefix is prepended to the name of each metric. The default is "http_".
+	Prefix string
+
+	buckets   []float64
+	mu        sync.Mutex
+	inFlight  int64
+	requests  map[requestSeries]uint64
+	durations map[routeSeries]*histogram
+	sizes     map[routeSeries]int64
+	limits    map[string]*ConcurrencyLimit
+}
+
+var _ Observer = &PrometheusObserver{}
+var _ http.Handler = &PrometheusObserver{}
+
+type requestSeries struct {
+	method, route, outcome string
+	status                 int
+}
+
+type routeSeries struct {
+	method, route string
+}
+
+type histogram struct {
+	counts []uint64 // one per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusObserver creates a PrometheusObserver. The buckets are the upper
+// bounds, in seconds, for the request duration histogram, in increasing order.
+// If no buckets are specified, DefaultDurationBuckets is used.
+func NewPrometheusObserver(buckets ...float64) *PrometheusObserver {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	return &PrometheusObserver{
+		Prefix:    "http_",
+		buckets:   buckets,
+		requests:  make(map[requestSeries]uint64),
+		durations: make(map[routeSeries]*histogram),
+		sizes:     make(map[routeSeries]int64),
+		limits:    make(map[string]*ConcurrencyLimit),
+	}
+}
+
+// WatchConcurrencyLimit adds metrics for a concurrency limit, i.e. the numbers
+// of requests in flight, queued and rejected, labelled by the name given.
+func (p *PrometheusObserver) WatchConcurrencyLimit(name string, limit *ConcurrencyLimit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limits[name] = limit
+}
+
+// OnRequestStart implements Observer.
+func (p *PrometheusObserver) OnRequestStart(_ *http.Request, _ RequestEvent) {
+	p.mu.Lock()
+	p.inFlight++
+	p.mu.Unlock()
+}
+
+// OnRequestEnd implements Observer.
+func (p *PrometheusObserver) OnRequestEnd(_ *http.Request, ev RequestEvent) {
+	seconds := ev.Duration.Seconds()
+	method := ev.Method
+	if !ev.registered && !contains(AllMethods, method) {
+		method = "other"
+	}
+	rs := routeSeries{method: method, route: ev.Pattern}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight--
+	p.requests[requestSeries{method: method, route: ev.Pattern, outcome: ev.Outcome.String(), status: ev.Status}]++
+	p.sizes[rs] += ev.Size
+
+	h := p.durations[rs]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[rs] = h
+	}
+	for i, le := range p.buckets {
+		if seconds <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *PrometheusObserver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	p.writeTo(bw)
+	bw.Flush()
+}
+
+func (p *PrometheusObserver) writeTo(w *bufio.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	name := p.Prefix + "requests_in_flight"
+	fmt.Fprintf(w, "# HELP %s Number of requests currently being served.\n", name)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, p.inFlight)
+
+	name = p.Prefix + "requests_total"
+	fmt.Fprintf(w, "# HELP %s Number of requests completed.\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	requests := make([]requestSeries, 0, len(p.requests))
+	for rs := range p.requests {
+		requests = append(requests, rs)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		if a.status != b.status {
+			return a.status < b.status
+		}
+		return a.outcome < b.outcome
+	})
+	for _, rs := range requests {
+		fmt.Fprintf(w, "%s{method=%s,route=%s,status=\"%d\",outcome=%s} %d\n",
+			name, quoteLabel(rs.method), quoteLabel(rs.route), rs.status, quoteLabel(rs.outcome), p.requests[rs])
+	}
+
+	routes := make([]routeSeries, 0, len(p.durations))
+	for rs := range p.durations {
+		routes = append(routes, rs)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].route != routes[j].route {
+			return routes[i].route < routes[j].route
+		}
+		return routes[i].method < routes[j].method
+	})
+
+	name = p.Prefix + "request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Time taken to serve requests.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, rs := range routes {
+		h := p.durations[rs]
+		labels := "method=" + quoteLabel(rs.method) + ",route=" + quoteLabel(rs.route)
+		var cumulative uint64
+		for i, le := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+
+	name = p.Prefix + "response_size_bytes_total"
+	fmt.Fprintf(w, "# HELP %s Number of bytes written in response bodies.\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, rs := range routes {
+		fmt.Fprintf(w, "%s{method=%s,route=%s} %d\n", name, quoteLabel(rs.method), quoteLabel(rs.route), p.sizes[rs])
+	}
+
+	if len(p.limits) > 0 {
+		p.writeLimits(w)
+	}
+}
+
+func (p *PrometheusObserver) writeLimits(w *bufio.Writer) {
+	names := make([]string, 0, len(p.limits))
+	for n := range p.limits {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	metrics := []struct {
+		name, help, kind string
+		value            func(*ConcurrencyLimit) uint64
+	}{
+		{"concurrency_in_flight", "Number of requests being served within a concurrency limit.", "gauge",
+			func(c *ConcurrencyLimit) uint64 { return uint64(c.InFlight()) }},
+		{"concurrency_queued", "Number of requests waiting for a concurrency limit.", "gauge",
+			func(c *ConcurrencyLimit) uint64 { return uint64(c.Queued()) }},
+		{"concurrency_rejected_total", "Number of requests rejected by a concurrency limit.", "counter",
+			(*ConcurrencyLimit).Rejected},
+	}
+
+	for _, m := range metrics {
+		name := p.Prefix + m.name
+		fmt.Fprintf(w, "# HELP %s %s\n", name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, m.kind)
+		for _, n := range names {
+			fmt.Fprintf(w, "%s{limit=%s} %d\n", name, quoteLabel(n), m.value(p.limits[n]))
+		}
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
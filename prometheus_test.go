@@ -0,0 +1,89 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrometheusObserver(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	metrics := NewPrometheusObserver(0.5, 1)
+
+	router := New()
+	router.Observer = metrics
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		w.Write([]byte("hello"))
+	})
+	router.Handler(http.MethodGet, "/metrics", metrics)
+
+	for _, url := range []string{"/users/1", "/users/2", "/nope"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	g.Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+	body := w.Body.String()
+	g.Expect(body).To(ContainSubstring("# TYPE http_requests_in_flight gauge\nhttp_requests_in_flight 1\n"))
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="GET",route="",status="404",outcome="not_found"} 1`))
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="GET",route="/users/:id",status="200",outcome="handled"} 2`))
+	g.Expect(body).To(ContainSubstring(`http_request_duration_seconds_bucket{method="GET",route="/users/:id",le="0.5"} 2`))
+	g.Expect(body).To(ContainSubstring(`http_request_duration_seconds_bucket{method="GET",route="/users/:id",le="+Inf"} 2`))
+	g.Expect(body).To(ContainSubstring(`http_request_duration_seconds_count{method="GET",route="/users/:id"} 2`))
+	g.Expect(body).To(ContainSubstring(`http_response_size_bytes_total{method="GET",route="/users/:id"} 10`))
+	g.Expect(body).NotTo(ContainSubstring("/users/1"))
+}
+
+func TestPrometheusObserver_otherMethods(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	metrics := NewPrometheusObserver()
+
+	router := New()
+	router.Observer = metrics
+	router.Handle("PURGE", "/cache", func(w http.ResponseWriter, r *http.Request, ps Params) {})
+
+	for _, method := range []string{"FOO", "BAR", http.MethodPut, "PURGE"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/nope", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PURGE", "/cache", nil))
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := w.Body.String()
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="other",route="",status="404",outcome="not_found"} 2`))
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="PUT",route="",status="404",outcome="not_found"} 1`))
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="PURGE",route="",status="404",outcome="not_found"} 1`))
+	g.Expect(body).To(ContainSubstring(`http_requests_total{method="PURGE",route="/cache",status="200",outcome="handled"} 1`))
+	g.Expect(body).NotTo(ContainSubstring("FOO"))
+}
+
+func TestQuoteLabel(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(quoteLabel("a\"b\\c\nd")).To(Equal(`"a\"b\\c\nd"`))
+}
+
+func TestPrometheusObserver_WatchConcurrencyLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &ConcurrencyLimit{MaxInFlight: 1}
+	metrics := NewPrometheusObserver()
+	metrics.WatchConcurrencyLimit("reports", limit)
+	limit.rejected.Add(2)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	g.Expect(w.Body.String()).To(ContainSubstring("# TYPE http_concurrency_in_flight gauge\nhttp_concurrency_in_flight{limit=\"reports\"} 0\n"))
+	g.Expect(w.Body.String()).To(ContainSubstring("# TYPE http_concurrency_queued gauge\nhttp_concurrency_queued{limit=\"reports\"} 0\n"))
+	g.Expect(w.Body.String()).To(ContainSubstring("# TYPE http_concurrency_rejected_total counter\nhttp_concurrency_rejected_total{limit=\"reports\"} 2\n"))
+}
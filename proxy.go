@@ -0,0 +1,391 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ProxyOptions configures the routes created by Router.Proxy. The zero value
+// is ready to use.
+type ProxyOptions struct {
+	// Path is the template of the upstream request path, which may use the
+	// parameters of the route's path, e.g. "/internal/users/:id" for the
+	// route "/api/users/:id", or "/v2/*filepath" for "/api/*filepath". It is
+	// appended to the path of the target. If it is blank, the request path
+	// is used unchanged.
+	Path string
+
+	// Targets are further upstream servers. Requests are shared between all
+	// the targets in round-robin order.
+	Targets []*url.URL
+
+	// Methods are the methods of the routes. If there are none, AllMethods is
+	// used.
+	Methods []string
+
+	// MaxFails is the number of consecutive failures after which a target is
+	// considered unhealthy. A failure is an error connecting to the target,
+	// or a 502, 503 or 504 response from it. If it is zero, one failure is
+	// enough; if it is negative, targets are always considered healthy.
+	MaxFails int
+
+	// FailTimeout is how long an unhealthy target is avoided, after which it
+	// is tried again. If it is zero, DefaultFailTimeout is used. If every
+	// target is unhealthy, they are tried anyway.
+	FailTimeout time.Duration
+
+	// PreserveHost keeps the Host header of the request, instead of using the
+	// host of the target.
+	PreserveHost bool
+
+	// TrustedProxies are the addresses of proxies whose Forwarded and
+	// X-Forwarded-* headers are passed on, with this hop added. Otherwise
+	// these headers are replaced, because they are easily forged.
+	TrustedProxies []netip.Prefix
+
+	// Transport makes the upstream requests. If it is nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// ModifyResponse, if not nil, alters the upstream responses (see
+	// httputil.ReverseProxy).
+	ModifyResponse func(*http.Response) error
+
+	// ErrorHandler, if not nil, answers requests that could not be proxied.
+	// Otherwise they are answered with 502 Bad Gateway.
+	ErrorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// DefaultFailTimeout is how long an unhealthy proxy target is avoided, if
+// its ProxyOptions have no FailTimeout.
+var DefaultFailTimeout = 10 * time.Second
+
+// Proxy forwards requests to upstream servers. It is created by Router.Proxy.
+type Proxy struct {
+	routes    []*Route
+	targets   []*upstream
+	next      atomic.Uint64
+	template  string
+	maxFails  int
+	failFor   time.Duration
+	opts      ProxyOptions
+	forwarder *httputil.ReverseProxy
+}
+
+// upstream is a target server and its passive health check.
+type upstream struct {
+	url       *url.URL
+	fails     atomic.Int64
+	downUntil atomic.Int64 // in Unix nanoseconds
+}
+
+// Proxy registers routes that forward requests to the target, using
+// httputil.ReverseProxy, e.g.
+//
+//	users, _ := url.Parse("http://users.internal:8080")
+//	router.Proxy("/api/users/:id", users, &httprouter.ProxyOptions{
+//		Path: "/internal/users/:id",
+//	})
+//
+// The upstream request has the Forwarded header (RFC 7239) and the
+// X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto headers, describing
+// the request. The query is passed on unchanged.
+//
+// If there are several targets, requests are shared between them in
+// round-robin order, skipping those that have recently failed (see
+// ProxyOptions.MaxFails). The opts may be nil.
+//
+// Requests whose path parameters contain a "." or ".." segment, which could
+// lead outside the upstream path, are answered with 400 Bad Request.
+func (r *Router) Proxy(path string, target *url.URL, opts *ProxyOptions) *Proxy {
+	if opts == nil {
+		opts = &ProxyOptions{}
+	}
+	if target == nil {
+		panic("proxy target must not be nil in path '" + path + "'")
+	}
+
+	p := &Proxy{
+		template: opts.Path,
+		maxFails: opts.MaxFails,
+		failFor:  opts.FailTimeout,
+		opts:     *opts,
+	}
+	if p.maxFails == 0 {
+		p.maxFails = 1
+	}
+	if p.failFor <= 0 {
+		p.failFor = DefaultFailTimeout
+	}
+
+	for _, u := range append([]*url.URL{target}, opts.Targets...) {
+		if u == nil || u.Scheme == "" || u.Host == "" {
+			panic("proxy target must be an absolute URL in path '" + path + "'")
+		}
+		p.targets = append(p.targets, &upstream{url: u})
+	}
+
+	if p.template != "" {
+		if p.template[0] != '/' {
+			panic("proxy path must begin with '/' in path '" + p.template + "'")
+		}
+		known := paramNames(path)
+		for _, name := range paramNames(p.template) {
+			if !contains(known, name) {
+				panic("proxy path parameter '" + name + "' is not in path '" + path + "'")
+			}
+		}
+	}
+
+	p.forwarder = &httputil.ReverseProxy{
+		Rewrite:        p.rewrite,
+		Transport:      opts.Transport,
+		ModifyResponse: p.modifyResponse,
+		ErrorHandler:   p.handleError,
+	}
+
+	methods := opts.Methods
+	if len(methods) == 0 {
+		methods = AllMethods
+	}
+	for _, m := range methods {
+		var rt *Route
+		rt = r.Handle(m, path, func(w http.ResponseWriter, req *http.Request, ps Params) {
+			for _, param := range ps {
+				if hasDotSegment(param.Value) {
+					http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+					return
+				}
+			}
+			p.forwarder.ServeHTTP(w, rt.withContext(req, ps))
+		})
+		rt.contextual = true
+		p.routes = append(p.routes, rt)
+	}
+
+	return p
+}
+
+// Routes gets the routes that forward requests, one per method.
+func (p *Proxy) Routes() []*Route {
+	return p.routes
+}
+
+// Healthy gets the targets that are currently considered healthy.
+func (p *Proxy) Healthy() []*url.URL {
+	now := time.Now().UnixNano()
+	var healthy []*url.URL
+	for _, t := range p.targets {
+		if t.healthy(now) {
+			healthy = append(healthy, t.url)
+		}
+	}
+	return healthy
+}
+
+func (t *upstream) healthy(now int64) bool {
+	return t.downUntil.Load() <= now
+}
+
+// pick chooses the next healthy target in round-robin order. If none is
+// healthy, the next one is chosen anyway.
+func (p *Proxy) pick() *upstream {
+	n := uint64(len(p.targets))
+	start := p.next.Add(1) - 1
+	now := time.Now().UnixNano()
+	for i := uint64(0); i < n; i++ {
+		if t := p.targets[(start+i)%n]; t.healthy(now) {
+			return t
+		}
+	}
+	return p.targets[start%n]
+}
+
+// failed records a failure of the target, marking it unhealthy if it has
+// failed too often.
+func (p *Proxy) failed(t *upstream) {
+	if p.maxFails < 0 {
+		return
+	}
+	if t.fails.Add(1) >= int64(p.maxFails) {
+		t.fails.Store(0)
+		t.downUntil.Store(time.Now().Add(p.failFor).UnixNano())
+	}
+}
+
+func (p *Proxy) succeeded(t *upstream) {
+	t.fails.Store(0)
+}
+
+// private type used for unique context keying
+type proxyTargetKey struct{}
+
+func (p *Proxy) rewrite(pr *httputil.ProxyRequest) {
+	t := p.pick()
+	u := t.url
+	pr.Out = pr.Out.WithContext(context.WithValue(pr.Out.Context(), proxyTargetKey{}, t))
+
+	pr.SetURL(u)
+	if p.template != "" {
+		var ps Params
+		if info := RouteFromContext(pr.In.Context()); info != nil {
+			ps = info.Params
+		}
+		// the parameters were all checked when the route was registered
+		path, _ := buildPath(p.template, ps)
+		pr.Out.URL.Path = strings.TrimSuffix(u.Path, "/") + unescapePath(path)
+		pr.Out.URL.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + path
+	}
+	if p.opts.PreserveHost {
+		pr.Out.Host = pr.In.Host
+	}
+
+	p.setForwarded(pr)
+}
+
+// setForwarded sets the Forwarded and X-Forwarded-* headers. Those of the
+// incoming request are kept only if it came from a trusted proxy.
+func (p *Proxy) setForwarded(pr *httputil.ProxyRequest) {
+	in, out := pr.In.Header, pr.Out.Header
+
+	host, _, err := net.SplitHostPort(pr.In.RemoteAddr)
+	if err != nil {
+		host = pr.In.RemoteAddr
+	}
+	trustedPeer := len(p.opts.TrustedProxies) > 0 && trusted(host, p.opts.TrustedProxies)
+
+	if trustedPeer {
+		out["X-Forwarded-For"] = in["X-Forwarded-For"]
+	}
+	pr.SetXForwarded()
+	if trustedPeer {
+		for _, h := range []string{"X-Forwarded-Host", "X-Forwarded-Proto"} {
+			if v := in.Get(h); v != "" {
+				out.Set(h, v)
+			}
+		}
+	}
+
+	proto := "http"
+	if pr.In.TLS != nil {
+		proto = "https"
+	}
+	element := "for=" + forwardedNode(host) + ";host=" + quoteForwarded(pr.In.Host) + ";proto=" + proto
+
+	if existing := in.Values("Forwarded"); trustedPeer && len(existing) > 0 {
+		element = strings.Join(existing, ", ") + ", " + element
+	}
+	out.Set("Forwarded", element)
+}
+
+// forwardedNode formats an address for the Forwarded header. IPv6 addresses
+// are bracketed and quoted, as RFC 7239 requires.
+func forwardedNode(host string) string {
+	if addr, err := netip.ParseAddr(host); err == nil && addr.Is6() && !addr.Is4In6() {
+		return `"[` + host + `]"`
+	}
+	return quoteForwarded(host)
+}
+
+// quoteForwarded quotes a Forwarded header value, if it is not a token.
+func quoteForwarded(v string) string {
+	if v != "" && strings.IndexFunc(v, func(c rune) bool { return !isTokenChar(c) }) < 0 {
+		return v
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
+
+func isTokenChar(c rune) bool {
+	return c < 0x7f && c > 0x20 && !strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c)
+}
+
+// hasDotSegment is true if a parameter value has a "." or ".." path segment,
+// even if it is percent-encoded.
+func hasDotSegment(value string) bool {
+	for _, seg := range strings.Split(unescapePath(value), "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func unescapePath(path string) string {
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		return unescaped
+	}
+	return path
+}
+
+func (p *Proxy) modifyResponse(resp *http.Response) error {
+	if t, ok := resp.Request.Context().Value(proxyTargetKey{}).(*upstream); ok {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			p.failed(t)
+		default:
+			p.succeeded(t)
+		}
+	}
+
+	if p.opts.ModifyResponse != nil {
+		if err := p.opts.ModifyResponse(resp); err != nil {
+			return modifyError{err}
+		}
+	}
+	return nil
+}
+
+// modifyError is an error from ProxyOptions.ModifyResponse, which is not a
+// failure of the target.
+type modifyError struct {
+	error
+}
+
+func (e modifyError) Unwrap() error {
+	return e.error
+}
+
+func (p *Proxy) handleError(w http.ResponseWriter, req *http.Request, err error) {
+	var me modifyError
+	if errors.As(err, &me) {
+		err = me.error
+	} else if t, ok := req.Context().Value(proxyTargetKey{}).(*upstream); ok && !errors.Is(err, context.Canceled) {
+		p.failed(t)
+	}
+
+	if p.opts.ErrorHandler != nil {
+		p.opts.ErrorHandler(w, req, err)
+	} else {
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+	}
+}
+
+// paramNames lists the names of the parameters in a path pattern.
+func paramNames(pattern string) []string {
+	var names []string
+	for {
+		i := strings.IndexAny(pattern, ":*")
+		if i < 0 {
+			return names
+		}
+		pattern = pattern[i+1:]
+		end := strings.IndexByte(pattern, '/')
+		if end < 0 {
+			end = len(pattern)
+		}
+		names = append(names, pattern[:end])
+		pattern = pattern[end:]
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// echoServer is an upstream that describes the requests it receives.
+func echoServer(t *testing.T, name string) (*httptest.Server, *url.URL) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h := w.Header()
+		h.Set("X-Upstream", name)
+		h.Set("X-Path", req.URL.Path)
+		h.Set("X-Raw-Path", req.URL.EscapedPath())
+		h.Set("X-Query", req.URL.RawQuery)
+		h.Set("X-Host", req.Host)
+		h.Set("X-Got-Forwarded", req.Header.Get("Forwarded"))
+		h.Set("X-Got-Forwarded-For", req.Header.Get("X-Forwarded-For"))
+		h.Set("X-Got-Forwarded-Host", req.Header.Get("X-Forwarded-Host"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	u, _ := url.Parse(server.URL)
+	return server, u
+}
+
+func proxied(router *Router, method, path string, header ...string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter_Proxy_paths(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, upstream := echoServer(t, "a")
+	base := *upstream
+	base.Path = "/base"
+
+	router := New()
+	router.Proxy("/api/users/:id", &base, &ProxyOptions{Path: "/internal/users/:id"})
+	router.Proxy("/svc/*filepath", upstream, nil)
+	router.Proxy("/v1/*filepath", upstream, &ProxyOptions{Path: "/v2/*filepath", Methods: []string{http.MethodGet}})
+
+	w := proxied(router, http.MethodGet, "/api/users/a%20b?x=1")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("X-Path")).To(Equal("/base/internal/users/a b"))
+	g.Expect(w.Header().Get("X-Raw-Path")).To(Equal("/base/internal/users/a%20b"))
+	g.Expect(w.Header().Get("X-Query")).To(Equal("x=1"))
+	g.Expect(w.Header().Get("X-Host")).To(Equal(upstream.Host))
+
+	w = proxied(router, http.MethodDelete, "/svc/a/b")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("X-Path")).To(Equal("/svc/a/b"))
+
+	w = proxied(router, http.MethodGet, "/v1/c/d")
+	g.Expect(w.Header().Get("X-Path")).To(Equal("/v2/c/d"))
+
+	w = proxied(router, http.MethodPost, "/v1/c/d")
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func TestRouter_Proxy_dotSegments(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, upstream := echoServer(t, "a")
+
+	router := New()
+	router.Proxy("/api/users/:id", upstream, &ProxyOptions{Path: "/internal/users/:id"})
+	router.Proxy("/files/*filepath", upstream, &ProxyOptions{Path: "/public/*filepath"})
+
+	for _, path := range []string{
+		"/api/users/..",
+		"/api/users/%2e%2e",
+		"/api/users/%2E",
+		"/files/../secret/x",
+		"/files/a/../../admin",
+		"/files/a/%2e%2e/%2e%2e/admin",
+		"/files/a/./b",
+	} {
+		w := proxied(router, http.MethodGet, path)
+		g.Expect(w.Code).To(Equal(http.StatusBadRequest), path)
+		g.Expect(w.Header().Get("X-Upstream")).To(BeEmpty(), path)
+	}
+
+	w := proxied(router, http.MethodGet, "/files/a/..b/c.")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("X-Path")).To(Equal("/public/a/..b/c."))
+}
+
+func TestRouter_Proxy_forwarded(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, upstream := echoServer(t, "a")
+
+	router := New()
+	router.Proxy("/open/*filepath", upstream, nil)
+	router.Proxy("/trusting/*filepath", upstream, &ProxyOptions{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+		PreserveHost:   true,
+	})
+
+	// httptest requests come from 192.0.2.1 for example.com
+	forged := []string{
+		"Forwarded", "for=198.51.100.7",
+		"X-Forwarded-For", "198.51.100.7",
+		"X-Forwarded-Host", "forged.example",
+	}
+
+	w := proxied(router, http.MethodGet, "/open/x", forged...)
+	g.Expect(w.Header().Get("X-Got-Forwarded")).To(Equal("for=192.0.2.1;host=example.com;proto=http"))
+	g.Expect(w.Header().Get("X-Got-Forwarded-For")).To(Equal("192.0.2.1"))
+	g.Expect(w.Header().Get("X-Got-Forwarded-Host")).To(Equal("example.com"))
+	g.Expect(w.Header().Get("X-Host")).To(Equal(upstream.Host))
+
+	w = proxied(router, http.MethodGet, "/trusting/x", forged...)
+	g.Expect(w.Header().Get("X-Got-Forwarded")).To(Equal("for=198.51.100.7, for=192.0.2.1;host=example.com;proto=http"))
+	g.Expect(w.Header().Get("X-Got-Forwarded-For")).To(Equal("198.51.100.7, 192.0.2.1"))
+	g.Expect(w.Header().Get("X-Got-Forwarded-Host")).To(Equal("forged.example"))
+	g.Expect(w.Header().Get("X-Host")).To(Equal("example.com"))
+
+	g.Expect(forwardedNode("2001:db8::1")).To(Equal(`"[2001:db8::1]"`))
+	g.Expect(quoteForwarded("example.com:8080")).To(Equal(`"example.com:8080"`))
+}
+
+func TestRouter_Proxy_roundRobin(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, a := echoServer(t, "a")
+	_, b := echoServer(t, "b")
+
+	router := New()
+	router.Proxy("/*filepath", a, &ProxyOptions{Targets: []*url.URL{b}})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, proxied(router, http.MethodGet, "/x").Header().Get("X-Upstream"))
+	}
+	g.Expect(got).To(Equal([]string{"a", "b", "a", "b"}))
+}
+
+func TestRouter_Proxy_healthChecks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	down, a := echoServer(t, "a")
+	down.Close()
+	_, b := echoServer(t, "b")
+
+	var proxyErr error
+	router := New()
+	p := router.Proxy("/*filepath", a, &ProxyOptions{
+		Targets: []*url.URL{b},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			proxyErr = err
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	})
+	g.Expect(p.Routes()).To(HaveLen(len(AllMethods)))
+	g.Expect(p.Healthy()).To(Equal([]*url.URL{a, b}))
+
+	w := proxied(router, http.MethodGet, "/x")
+	g.Expect(w.Code).To(Equal(http.StatusBadGateway))
+	g.Expect(proxyErr).To(HaveOccurred())
+	g.Expect(p.Healthy()).To(Equal([]*url.URL{b}))
+
+	for i := 0; i < 3; i++ {
+		w = proxied(router, http.MethodGet, "/x")
+		g.Expect(w.Code).To(Equal(http.StatusOK))
+		g.Expect(w.Header().Get("X-Upstream")).To(Equal("b"))
+	}
+}
+
+func TestRouter_Proxy_unavailable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	busy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer busy.Close()
+	a, _ := url.Parse(busy.URL)
+
+	router := New()
+	p := router.Proxy("/*filepath", a, &ProxyOptions{MaxFails: 2})
+
+	g.Expect(proxied(router, http.MethodGet, "/x").Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(p.Healthy()).To(HaveLen(1))
+
+	g.Expect(proxied(router, http.MethodGet, "/x").Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(p.Healthy()).To(BeEmpty())
+
+	// every target is unhealthy, so they are tried anyway
+	g.Expect(proxied(router, http.MethodGet, "/x").Code).To(Equal(http.StatusServiceUnavailable))
+}
+
+func TestRouter_Proxy_panics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	u, _ := url.Parse("http://example.com")
+
+	router := New()
+	g.Expect(func() { router.Proxy("/a/:id", nil, nil) }).To(Panic())
+	g.Expect(func() { router.Proxy("/b/:id", &url.URL{Path: "/x"}, nil) }).To(Panic())
+	g.Expect(func() { router.Proxy("/c/:id", u, &ProxyOptions{Path: "/c/:name"}) }).To(Panic())
+	g.Expect(func() { router.Proxy("/d/:id", u, &ProxyOptions{Path: "d/:id"}) }).To(Panic())
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitMetaKey is the route metadata key under which a route's
+// *RateLimit is held (see Route.WithRateLimit).
+const RateLimitMetaKey = "rateLimit"
+
+// RateLimit is a token-bucket rate limit, e.g.
+//
+//	limit := &httprouter.RateLimit{Requests: 100, Period: time.Minute}
+//	router.GET("/search", search).WithRateLimit(limit)
+//
+// Each client has its own bucket for each route (i.e. method and path
+// pattern). The bucket holds up to Burst tokens and is refilled at the rate of
+// Requests per Period. Each request takes a token; requests that find the
+// bucket empty are answered using Router.TooManyRequests.
+//
+// A RateLimit may be shared by many routes, but must not be altered once it
+// is in use.
+type RateLimit struct {
+	// Requests is the number of requests allowed per Period.
+	Requests int
+	// Period is the duration over which Requests are allowed.
+	Period time.Duration
+	// Burst is the size of the bucket, i.e. how many requests can be made in
+	// quick succession. If it is zero, Requests is used.
+	Burst int
+	// Key identifies the client that made a request. If it is nil,
+	// ByClientIP() is used. Requests with the same key share a bucket.
+	Key func(req *http.Request) string
+	// Store holds the buckets. If it is nil, a MemoryStore holding up to
+	// DefaultMaxKeys buckets is used.
+	Store RateLimitStore
+
+	store RateLimitStore
+}
+
+// String describes the limit, e.g. "100 per 1m0s, burst 100".
+func (l *RateLimit) String() string {
+	return fmt.Sprintf("%d per %s, burst %d", l.Requests, l.Period, l.burst())
+}
+
+func (l *RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate gets the rate of refill in tokens per second.
+func (l *RateLimit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ByClientIP identifies clients by their address (see ClientIP).
+func ByClientIP(trustedProxies ...netip.Prefix) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		return ClientIP(req, trustedProxies)
+	}
+}
+
+// ByHeader identifies clients by a request header, such as an API key. All
+// requests that lack the header share a single bucket.
+func ByHeader(name string) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		return req.Header.Get(name)
+	}
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket.
+type RateLimitDecision struct {
+	// Allowed is true if a token was taken.
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// RetryAfter is how long until a token will be available, if none was.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket will be full.
+	Reset time.Duration
+}
+
+// RateLimitStore holds token buckets. Implementations must be safe for
+// concurrent use. MemoryStore is provided; others might keep the buckets in a
+// shared database so that limits apply across a cluster of servers.
+type RateLimitStore interface {
+	// Take attempts to take a token from the bucket identified by the key,
+	// creating a full bucket if it does not exist.
+	Take(key string, limit *RateLimit, now time.Time) RateLimitDecision
+}
+
+// DefaultMaxKeys is the number of buckets held by the MemoryStore used for
+// rate limits that have no Store.
+var DefaultMaxKeys = 10000
+
+// MemoryStore is a RateLimitStore that holds buckets in memory. When the
+// number of buckets reaches its limit, the least recently used bucket is
+// discarded, so memory use is bounded.
+type MemoryStore struct {
+	maxKeys int
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List // of *bucket, most recently used first
+}
+
+var _ RateLimitStore = &MemoryStore{}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// NewMemoryStore creates a MemoryStore that holds up to maxKeys buckets.
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys < 1 {
+		panic("maxKeys must be positive")
+	}
+	return &MemoryStore{
+		maxKeys: maxKeys,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Len gets the number of buckets held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryStore) Take(key string, limit *RateLimit, now time.Time) RateLimitDecision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	burst, rate := float64(limit.burst()), limit.rate()
+
+	var b *bucket
+	if e, exists := s.buckets[key]; exists {
+		s.lru.MoveToFront(e)
+		b = e.Value.(*bucket)
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+		b.last = now
+	} else {
+		if s.lru.Len() >= s.maxKeys {
+			oldest := s.lru.Back()
+			s.lru.Remove(oldest)
+			delete(s.buckets, oldest.Value.(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: burst, last: now}
+		s.buckets[key] = s.lru.PushFront(b)
+	}
+
+	d := RateLimitDecision{}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((burst - b.tokens) / rate)
+	return d
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// WithRateLimit applies a rate limit to the route. The limit is also held in
+// the route's metadata under RateLimitMetaKey, so that it is visible in route
+// listings and to middleware. A nil limit removes the rate limit.
+func (rt *Route) WithRateLimit(limit *RateLimit) *Route {
+	if limit != nil && (limit.Requests < 1 || limit.Period <= 0) {
+		panic("rate limit must have positive Requests and Period in path '" + rt.path + "'")
+	}
+
+	return rt.refine(func() {
+		if limit == nil {
+			delete(rt.info.Metadata, RateLimitMetaKey)
+		} else {
+			rt.WithMeta(RateLimitMetaKey, limit)
+		}
+	})
+}
+
+// RateLimit gets the rate limit of the route, or nil if it has none.
+func (rt *Route) RateLimit() *RateLimit {
+	limit, _ := rt.Meta(RateLimitMetaKey).(*RateLimit)
+	return limit
+}
+
+// withRateLimit wraps the handle so that requests are limited.
+func (r *Router) withRateLimit(handle Handle, rt *Route, limit *RateLimit) Handle {
+	store := limit.Store
+	if store == nil {
+		if limit.store == nil {
+			limit.store = NewMemoryStore(DefaultMaxKeys)
+		}
+		store = limit.store
+	}
+
+	key := limit.Key
+	if key == nil {
+		key = ByClientIP()
+	}
+
+	prefix := rt.method + " " + rt.path + " "
+	burst := strconv.Itoa(limit.burst())
+	policy := burst + ";w=" + strconv.FormatInt(int64(math.Ceil(limit.Period.Seconds())), 10)
+
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		d := store.Take(prefix+key(req), limit, time.Now())
+
+		h := w.Header()
+		h.Set("RateLimit-Policy", policy)
+		h.Set("RateLimit-Limit", burst)
+		h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		h.Set("RateLimit-Reset", ceilSeconds(d.Reset))
+
+		if !d.Allowed {
+			h.Set("Retry-After", ceilSeconds(d.RetryAfter))
+			if r.TooManyRequests != nil {
+				r.TooManyRequests.ServeHTTP(w, req)
+			} else {
+				http.Error(w,
+					http.StatusText(http.StatusTooManyRequests),
+					http.StatusTooManyRequests,
+				)
+			}
+			return
+		}
+
+		handle(w, req, ps)
+	}
+}
+
+// ceilSeconds formats a duration as a whole number of seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
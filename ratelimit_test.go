@@ -0,0 +1,121 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &RateLimit{Requests: 2, Period: time.Second, Burst: 3}
+	store := NewMemoryStore(10)
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g.Expect(store.Take("a", limit, t0)).To(Equal(RateLimitDecision{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond}))
+	g.Expect(store.Take("a", limit, t0)).To(Equal(RateLimitDecision{Allowed: true, Remaining: 1, Reset: time.Second}))
+	g.Expect(store.Take("a", limit, t0)).To(Equal(RateLimitDecision{Allowed: true, Remaining: 0, Reset: 1500 * time.Millisecond}))
+	g.Expect(store.Take("a", limit, t0)).To(Equal(RateLimitDecision{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond}))
+
+	// another client is unaffected
+	g.Expect(store.Take("b", limit, t0).Allowed).To(BeTrue())
+
+	// refilled at 2 per second
+	g.Expect(store.Take("a", limit, t0.Add(500*time.Millisecond)).Allowed).To(BeTrue())
+	g.Expect(store.Take("a", limit, t0.Add(500*time.Millisecond)).Allowed).To(BeFalse())
+	g.Expect(store.Take("a", limit, t0.Add(time.Hour))).To(Equal(RateLimitDecision{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond}))
+}
+
+func TestMemoryStore_bounded(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &RateLimit{Requests: 1, Period: time.Hour}
+	store := NewMemoryStore(2)
+	now := time.Now()
+
+	g.Expect(store.Take("a", limit, now).Allowed).To(BeTrue())
+	g.Expect(store.Take("b", limit, now).Allowed).To(BeTrue())
+	g.Expect(store.Take("a", limit, now).Allowed).To(BeFalse()) // a is now most recent
+	g.Expect(store.Take("c", limit, now).Allowed).To(BeTrue())  // evicts b
+	g.Expect(store.Len()).To(Equal(2))
+
+	g.Expect(store.Take("a", limit, now).Allowed).To(BeFalse())
+	g.Expect(store.Take("b", limit, now).Allowed).To(BeTrue()) // forgotten, so full again
+}
+
+func TestRoute_WithRateLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	limit := &RateLimit{Requests: 1, Period: time.Minute, Key: ByHeader("X-API-Key")}
+	router.GET("/search", noop).WithRateLimit(limit)
+	router.GET("/other", noop).WithRateLimit(limit)
+
+	send := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/search", "k1")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("RateLimit-Policy")).To(Equal("1;w=60"))
+	g.Expect(w.Header().Get("RateLimit-Limit")).To(Equal("1"))
+	g.Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("0"))
+	g.Expect(w.Header().Get("RateLimit-Reset")).To(Equal("60"))
+
+	w = send("/search", "k1")
+	g.Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+	g.Expect(w.Header().Get("Retry-After")).To(Equal("60"))
+
+	g.Expect(send("/search", "k2").Code).To(Equal(http.StatusOK))
+	g.Expect(send("/other", "k1").Code).To(Equal(http.StatusOK))
+}
+
+func TestRouter_TooManyRequests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.TooManyRequests = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	api := router.Group("/api").WithRateLimit(&RateLimit{Requests: 1, Period: time.Minute})
+	api.GET("/a", noop)
+
+	codes := []int{}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/a", nil))
+		codes = append(codes, w.Code)
+	}
+
+	g.Expect(codes).To(Equal([]int{http.StatusOK, http.StatusServiceUnavailable}))
+}
+
+func TestRoute_WithRateLimit_listing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := &RateLimit{Requests: 10, Period: time.Second, Burst: 20}
+	router := New()
+	router.GET("/limited", noop).WithRateLimit(limit)
+	router.GET("/removed", noop).WithRateLimit(limit).WithRateLimit(nil)
+
+	routes := router.Routes()
+	g.Expect(routes[0].RateLimit()).To(BeIdenticalTo(limit))
+	g.Expect(routes[0].Meta(RateLimitMetaKey)).To(BeIdenticalTo(limit))
+	g.Expect(routes[0].RateLimit().String()).To(Equal("10 per 1s, burst 20"))
+	g.Expect(routes[1].RateLimit()).To(BeNil())
+
+	g.Expect(func() {
+		router.GET("/bad", noop).WithRateLimit(&RateLimit{Requests: 1})
+	}).To(Panic())
+}
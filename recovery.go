@@ -0,0 +1,116 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery provides a PanicHandler that logs each panic with its stack trace
+// and replies with 500 Internal Server Error, e.g.
+//
+//	(&httprouter.Recovery{}).Install(router)
+//
+// The reply is an RFC 9457 problem (application/problem+json) if the client
+// prefers that to text, otherwise it is plain text. No reply is written if the
+// response had already been committed before the panic. This is known from the
+// writer's Committed method, as provided by StatusWriter; Install makes the
+// router provide this.
+type Recovery struct {
+	// Logger receives a record for each panic. If it is nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+}
+
+// Install sets the router's PanicHandler to HandlePanic. The router then wraps
+// the writer of each request that it hands to a route in a StatusWriter, unless
+// it already has a Committed method, so that HandlePanic can tell whether the
+// response has been committed.
+func (rc *Recovery) Install(router *Router) {
+	router.PanicHandler = rc.HandlePanic
+	router.tracksCommit = true
+}
+
+// HandlePanic logs the panic and writes a 500 reply. It is suitable for
+// Router.PanicHandler. It must be called by the function that recovered the
+// panic (as the router does), so that the stack trace is still available.
+func (rc *Recovery) HandlePanic(w http.ResponseWriter, req *http.Request, rcv interface{}) {
+	logger := rc.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	stack := debug.Stack()
+	if hp, ok := rcv.(*HandlerPanic); ok {
+		rcv, stack = hp.Value, hp.Stack
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("panic", fmt.Sprint(rcv)),
+		slog.String("stack", string(stack)),
+	}
+	id := RequestIDFromContext(req.Context())
+	if id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	logger.LogAttrs(context.Background(), slog.LevelError, "panic", attrs...)
+
+	if cw, ok := w.(interface{ Committed() bool }); ok && cw.Committed() {
+		return // too late to change the response
+	}
+
+	ranges := parseAccept(req.Header.Values("Accept"))
+	if len(ranges) > 0 && quality("application/problem+json", ranges) > quality("text/plain", ranges) {
+		writeProblem(w, http.StatusInternalServerError, id)
+	} else {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// HandlerPanic is passed to the PanicHandler in place of the recovered value
+// when a handle panics in a goroutine of its own, as it does with a timeout
+// (see Route.WithTimeout), so that the stack trace of that goroutine is not
+// lost. Recovery.HandlePanic logs the value and this stack trace.
+type HandlerPanic struct {
+	// Value is the value that was passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// String gets the panic value as text.
+func (p *HandlerPanic) String() string {
+	return fmt.Sprint(p.Value)
+}
+
+// problem is an RFC 9457 problem detail.
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// writeProblem writes an RFC 9457 problem for the status code.
+func writeProblem(w http.ResponseWriter, code int, requestID string) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/problem+json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(code),
+		Status:    code,
+		RequestID: requestID,
+	})
+}
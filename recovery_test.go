@@ -0,0 +1,152 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/onsi/gomega"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func panickingRouter(buf *bytes.Buffer) *Router {
+	router := New()
+	(&Recovery{Logger: slog.New(slog.NewJSONHandler(buf, nil))}).Install(router)
+	router.GET("/boom", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		panic("oops")
+	})
+	router.GET("/late", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.Write([]byte("partial"))
+		panic("oops")
+	})
+	router.GET("/accepted", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("oops")
+	})
+	router.GET("/abort", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		panic(http.ErrAbortHandler)
+	})
+	return router
+}
+
+func TestRecovery_text(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	router := panickingRouter(buf)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	g.Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/plain"))
+	g.Expect(w.Body.String()).To(Equal("Internal Server Error\n"))
+
+	var record map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+	g.Expect(record).To(HaveKeyWithValue("level", "ERROR"))
+	g.Expect(record).To(HaveKeyWithValue("msg", "panic"))
+	g.Expect(record).To(HaveKeyWithValue("panic", "oops"))
+	g.Expect(record).To(HaveKeyWithValue("path", "/boom"))
+	g.Expect(record["stack"]).To(ContainSubstring("panickingRouter"))
+}
+
+func TestRecovery_problem_json(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := panickingRouter(&bytes.Buffer{})
+	router.Use((&RequestID{Generate: func() string { return "r1" }}).Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	g.Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+	g.Expect(w.Body.String()).To(MatchJSON(`{"type":"about:blank","title":"Internal Server Error","status":500,"request_id":"r1"}`))
+}
+
+func TestRecovery_committed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	router := panickingRouter(buf)
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(NewStatusWriter(w), req)
+		})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/late", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("partial"))
+	g.Expect(buf.String()).To(ContainSubstring(`"panic":"oops"`))
+}
+
+func TestRecovery_committed_without_middleware(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	router := panickingRouter(buf)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accepted", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusAccepted))
+	g.Expect(w.Body.String()).To(Equal("partial"))
+	g.Expect(buf.String()).To(ContainSubstring(`"panic":"oops"`))
+}
+
+func TestRouter_PanicHandler_ErrAbortHandler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	router := panickingRouter(buf)
+
+	g.Expect(func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	}).To(PanicWith(http.ErrAbortHandler))
+	g.Expect(buf.Len()).To(BeZero())
+}
+
+type pushingRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (p *pushingRecorder) Push(target string, _ *http.PushOptions) error {
+	p.pushed = append(p.pushed, target)
+	return nil
+}
+
+func TestRouter_PanicHandler_keeps_writer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var seen http.ResponseWriter
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, req *http.Request, rcv interface{}) {}
+	router.GET("/push", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		seen = w
+	})
+
+	// without Recovery.Install, the writer is passed on as it is
+	w := &pushingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/push", nil))
+	g.Expect(seen).To(BeIdenticalTo(w))
+
+	// with it, the writer is wrapped but can still push
+	(&Recovery{}).Install(router)
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/push", nil))
+	g.Expect(seen).To(BeAssignableToTypeOf(&StatusWriter{}))
+	g.Expect(seen.(http.Pusher).Push("/app.css", nil)).To(Succeed())
+	g.Expect(w.pushed).To(Equal([]string{"/app.css"}))
+}
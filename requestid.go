@@ -0,0 +1,156 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// RequestID is middleware that gives every request an ID, e.g.
+//
+//	router.Use((&httprouter.RequestID{}).Middleware)
+//
+// The ID is taken from the request header if present, otherwise it is
+// generated. It is stored in the request context, where it can be obtained
+// using RequestIDFromContext, and it is echoed in the response header.
+//
+// When installed via Router.Use, the ID is available to the PanicHandler and
+// is included in the responses that the router generates itself, such as
+// 404 and 405 replies.
+type RequestID struct {
+	// Header is the name of the request and response header holding the ID.
+	// The default is "X-Request-ID".
+	Header string
+
+	// Generate creates a new ID. The default is NewULID.
+	Generate func() string
+
+	// IgnoreIncoming prevents IDs being taken from the request header, so
+	// every request is given a new ID. This is appropriate where clients are
+	// not trusted.
+	IgnoreIncoming bool
+}
+
+// maxRequestIDLength limits incoming IDs; longer IDs are replaced.
+const maxRequestIDLength = 128
+
+// Middleware wraps the next handler so that every request has an ID. It is
+// a Middleware suitable for Router.Use.
+func (rid *RequestID) Middleware(next http.Handler) http.Handler {
+	header := rid.Header
+	if header == "" {
+		header = "X-Request-ID"
+	}
+
+	generate := rid.Generate
+	if generate == nil {
+		generate = NewULID
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := ""
+		if !rid.IgnoreIncoming {
+			id = req.Header.Get(header)
+		}
+		if !validRequestID(id) {
+			id = generate()
+		}
+
+		// outer middleware, such as AccessLog, finds the ID in the response
+		w.Header().Set(header, id)
+		next.ServeHTTP(w, req.WithContext(WithRequestID(req.Context(), id)))
+	})
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII characters that are
+// not unreasonably long.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// private type used for unique context keying
+type requestIDKey struct{}
+
+// WithRequestID adds the request ID into the context. A modified context is
+// returned.
+func WithRequestID(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, requestIDKey{}, id)
+}
+
+// RequestIDFromContext gets the request ID from a request context, or returns
+// a blank string if none is present.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// crockford is the Crockford base 32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID generates a ULID, i.e. a 26-character identifier that starts with
+// a millisecond timestamp, so that IDs sort by time, followed by 80 random
+// bits. See https://github.com/ulid/spec.
+func NewULID() string {
+	var b [16]byte
+	putTimestamp(b[:], time.Now())
+	rand.Read(b[6:])
+
+	// 128 bits encode as 26 characters of 5 bits; the first has only 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// NewUUIDv7 generates a version 7 UUID, i.e. one that starts with a
+// millisecond timestamp, so that IDs sort by time, followed by random bits.
+// See RFC 9562.
+func NewUUIDv7() string {
+	var b [16]byte
+	putTimestamp(b[:], time.Now())
+	rand.Read(b[6:])
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // variant 10
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// putTimestamp writes the Unix time in milliseconds as 48 bits, big-endian.
+func putTimestamp(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
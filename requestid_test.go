@@ -0,0 +1,156 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	. "github.com/onsi/gomega"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestID_generated(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var seen string
+	router := New()
+	router.Use((&RequestID{}).Middleware)
+	router.GET("/a", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		seen = RequestIDFromContext(req.Context())
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+
+	g.Expect(seen).To(MatchRegexp(`^[0-9A-HJKMNP-TV-Z]{26}$`))
+	g.Expect(w.Header().Get("X-Request-ID")).To(Equal(seen))
+}
+
+func TestRequestID_incoming(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var seen []string
+	router := New()
+	router.Use((&RequestID{Header: "X-Correlation-ID"}).Middleware)
+	router.GET("/a", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		seen = append(seen, RequestIDFromContext(req.Context()))
+	})
+
+	for _, id := range []string{"abc-123", "has space", strings.Repeat("x", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/a", nil)
+		req.Header.Set("X-Correlation-ID", id)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		g.Expect(w.Header().Get("X-Correlation-ID")).To(Equal(seen[len(seen)-1]))
+	}
+
+	g.Expect(seen[0]).To(Equal("abc-123"))
+	g.Expect(seen[1]).To(HaveLen(26))
+	g.Expect(seen[2]).To(HaveLen(26))
+}
+
+func TestRequestID_ignore_incoming(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.Use((&RequestID{IgnoreIncoming: true, Generate: func() string { return "new" }}).Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/a", nil)
+	req.Header.Set("X-Request-ID", "old")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+	g.Expect(w.Header().Get("X-Request-ID")).To(Equal("new"))
+}
+
+func TestRequestID_router_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var panicked string
+	router := New()
+	router.Use((&RequestID{Generate: func() string { return "id1" }}).Middleware)
+	router.PanicHandler = func(w http.ResponseWriter, req *http.Request, _ interface{}) {
+		panicked = RequestIDFromContext(req.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.GET("/boom", func(w http.ResponseWriter, req *http.Request, _ Params) {
+		panic("boom")
+	})
+
+	for path, code := range map[string]int{"/boom": 500, "/nope": 404} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(w.Code).To(Equal(code))
+		g.Expect(w.Header().Get("X-Request-ID")).To(Equal("id1"))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/boom", nil))
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("X-Request-ID")).To(Equal("id1"))
+
+	g.Expect(panicked).To(Equal("id1"))
+}
+
+func TestRequestID_access_log(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	accessLog := &AccessLog{Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+
+	router := New()
+	router.Use(accessLog.Middleware, (&RequestID{Generate: func() string { return "id2" }}).Middleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/a", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+	g.Expect(record).To(HaveKeyWithValue("request_id", "id2"))
+
+	// the inbound request is not altered
+	g.Expect(req.Header).NotTo(HaveKey("X-Request-Id"))
+}
+
+func TestRequestIDFromContext_absent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(RequestIDFromContext(context.Background())).To(BeEmpty())
+}
+
+func TestNewULID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewULID()
+	time.Sleep(2 * time.Millisecond)
+	b := NewULID()
+
+	g.Expect(a).To(MatchRegexp(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`))
+	g.Expect(a < b).To(BeTrue())
+
+	// the timestamp occupies the first 10 characters
+	var ms uint64
+	for _, c := range a[:10] {
+		ms = ms<<5 | uint64(strings.IndexRune(crockford, c))
+	}
+	g.Expect(time.Since(time.UnixMilli(int64(ms)))).To(BeNumerically("<", time.Second))
+}
+
+func TestNewUUIDv7(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewUUIDv7()
+	time.Sleep(2 * time.Millisecond)
+	b := NewUUIDv7()
+
+	g.Expect(a).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	g.Expect(a < b).To(BeTrue())
+}
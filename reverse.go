@@ -0,0 +1,86 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NamedRoute gets the route with the given name (see Route.Named), or nil if
+// there is none. If more than one route has the name, the first listed by
+// Routes is returned.
+func (r *Router) NamedRoute(name string) *Route {
+	for _, rt := range r.Routes() {
+		if rt.info.Name == name {
+			return rt
+		}
+	}
+	return nil
+}
+
+// Reverse builds the path of the named route, substituting the parameters
+// into its pattern (see Route.Build), e.g.
+//
+//	router.GET("/users/:id", showUser).Named("user")
+//	path, err := router.Reverse("user", httprouter.Params{{Key: "id", Value: "42"}})
+//	// path is "/users/42"
+func (r *Router) Reverse(name string, ps Params) (string, error) {
+	rt := r.NamedRoute(name)
+	if rt == nil {
+		return "", fmt.Errorf("no route is named %q", name)
+	}
+	return rt.Build(ps)
+}
+
+// Build builds a request path from the route's pattern, substituting the
+// parameters. Values of named parameters are escaped as required; they must
+// not be blank. Values of catch-all parameters may contain "/" and may be
+// blank or absent.
+func (rt *Route) Build(ps Params) (string, error) {
+	return buildPath(rt.path, ps)
+}
+
+// buildPath substitutes the parameters into a path pattern.
+func buildPath(pattern string, ps Params) (string, error) {
+	original := pattern
+	buf := &strings.Builder{}
+
+	for {
+		i := strings.IndexAny(pattern, ":*")
+		if i < 0 {
+			buf.WriteString(pattern)
+			return buf.String(), nil
+		}
+		buf.WriteString(pattern[:i])
+
+		wildcard := pattern[i]
+		end := strings.IndexByte(pattern[i:], '/')
+		if end < 0 {
+			end = len(pattern) - i
+		}
+		name := pattern[i+1 : i+end]
+		pattern = pattern[i+end:]
+
+		value := ps.ByName(name)
+
+		if wildcard == ':' {
+			if value == "" {
+				return "", fmt.Errorf("missing parameter %q for path '%s'", name, original)
+			}
+			buf.WriteString(url.PathEscape(value))
+			continue
+		}
+
+		// the catch-all value includes the '/' that precedes it
+		value = strings.TrimPrefix(value, "/")
+		segments := strings.Split(value, "/")
+		for j, s := range segments {
+			segments[j] = url.PathEscape(s)
+		}
+		buf.WriteString(strings.Join(segments, "/"))
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRoute_Build(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+
+	cases := []struct {
+		pattern string
+		ps      Params
+		path    string
+	}{
+		{pattern: "/", path: "/"},
+		{pattern: "/a/b", path: "/a/b"},
+		{pattern: "/users/:id", ps: Params{{Key: "id", Value: "42"}}, path: "/users/42"},
+		{pattern: "/users/:id/posts/:post", ps: Params{{Key: "post", Value: "a b"}, {Key: "id", Value: "x/y"}}, path: "/users/x%2Fy/posts/a%20b"},
+		{pattern: "/files/*filepath", ps: Params{{Key: "filepath", Value: "/a/b c.txt"}}, path: "/files/a/b%20c.txt"},
+		{pattern: "/src/*filepath", ps: Params{{Key: "filepath", Value: "d/e"}}, path: "/src/d/e"},
+		{pattern: "/all/*rest", path: "/all/"},
+	}
+
+	for _, c := range cases {
+		path, err := router.GET(c.pattern, noop).Build(c.ps)
+		g.Expect(err).NotTo(HaveOccurred(), c.pattern)
+		g.Expect(path).To(Equal(c.path), c.pattern)
+	}
+
+	_, err := router.GET("/things/:id", noop).Build(nil)
+	g.Expect(err).To(MatchError(`missing parameter "id" for path '/things/:id'`))
+}
+
+func TestRouter_Reverse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop).Named("user")
+	router.POST("/users", noop).Named("users")
+
+	g.Expect(router.NamedRoute("users").Method()).To(Equal("POST"))
+	g.Expect(router.NamedRoute("nope")).To(BeNil())
+
+	path, err := router.Reverse("user", Params{{Key: "id", Value: "1"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal("/users/1"))
+
+	_, err = router.Reverse("nope", nil)
+	g.Expect(err).To(MatchError(`no route is named "nope"`))
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Route is a route that has been registered with a Router. Its methods allow
+// the route to be refined after it has been registered, e.g.
+//
+//	router.GET("/items/:id", handle).Produces("application/json")
+//
+// Routes must only be refined while the router is being set up, i.e. before
+// it starts serving requests.
+type Route struct {
+	method      string
+	path        string
+	handle      Handle
+	serve       Handle // the handle wrapped according to the route's limits
+	timeout     time.Duration
+	maxBodySize int64
+	produces    []string
+	matchers    []Matcher
+	info        RouteInfo
+	contextual  bool // true for http.Handler routes, which use the request context
+	router      *Router
+}
+
+func newRoute(r *Router, method, path string, handle Handle) *Route {
+	return &Route{
+		method: method,
+		path:   path,
+		handle: handle,
+		info:   RouteInfo{Method: method, Pattern: path},
+		router: r,
+
+		maxBodySize: r.MaxBodySize,
+	}
+}
+
+// Method gets the request method of the route.
+func (rt *Route) Method() string {
+	return rt.method
+}
+
+// Path gets the path pattern of the route, as registered.
+func (rt *Route) Path() string {
+	return rt.path
+}
+
+// Name gets the name of the route, which is blank unless set by Named.
+func (rt *Route) Name() string {
+	return rt.info.Name
+}
+
+// Named sets the name of the route. This is available to handlers via
+// RouteFromContext.
+func (rt *Route) Named(name string) *Route {
+	rt.info.Name = name
+	return rt
+}
+
+// Meta gets a metadata value of the route, or nil if it is not set.
+func (rt *Route) Meta(key string) any {
+	return rt.info.Metadata[key]
+}
+
+// WithMeta sets a metadata value of the route. Metadata is available to
+// handlers and middleware via RouteFromContext.
+func (rt *Route) WithMeta(key string, value any) *Route {
+	if rt.info.Metadata == nil {
+		rt.info.Metadata = make(map[string]any)
+	}
+	rt.info.Metadata[key] = value
+	return rt
+}
+
+// refine applies a change to the route and rebuilds its handles.
+func (rt *Route) refine(change func()) *Route {
+	change()
+	rt.router.refresh(routeKey{method: rt.method, path: rt.path})
+	return rt
+}
+
+// wrap builds the handle that serves the route, which is the route's handle
+// wrapped according to the route's limits. The concurrency limit is innermost,
+// so that a handle that outlives its timeout keeps its slot until it returns.
+func (rt *Route) wrap() Handle {
+	handle := rt.handle
+	if limit := rt.ConcurrencyLimit(); limit != nil {
+		handle = rt.router.withConcurrencyLimit(handle, limit)
+	}
+	if rt.timeout > 0 {
+		handle = rt.router.withTimeout(handle, rt.timeout)
+	}
+	if rt.maxBodySize > 0 {
+		handle = rt.router.withMaxBodySize(handle, rt.maxBodySize)
+	}
+	if limit := rt.RateLimit(); limit != nil {
+		handle = rt.router.withRateLimit(handle, rt, limit)
+	}
+	return handle
+}
+
+// conditional is true if the route only handles some of the requests that
+// match its method and path.
+func (rt *Route) conditional() bool {
+	return len(rt.produces) > 0 || len(rt.matchers) > 0
+}
+
+// routeKey identifies the routes that share a method and path.
+type routeKey struct {
+	method, path string
+}
+
+// addVariant registers a route for a method and path that already has
+// routes. This is allowed only if the existing routes are conditional.
+func (r *Router) addVariant(rt *Route) {
+	key := routeKey{method: rt.method, path: rt.path}
+	for _, existing := range r.routes[key] {
+		if !existing.conditional() {
+			panic(newConflict(rt.method, rt.path, existing.path, "",
+				"a handle is already registered for path '"+rt.path+"'"))
+		}
+	}
+	r.routes[key] = append(r.routes[key], rt)
+	r.refresh(key)
+}
+
+// refresh rebuilds the handle held in the tree for the routes that share a
+// method and path. In the usual case of a single unconditional route, this is
+// simply the route's own handle.
+func (r *Router) refresh(key routeKey) {
+	routes := r.routes[key]
+	for _, rt := range routes {
+		rt.serve = rt.wrap()
+	}
+
+	handle, route := routes[0].serve, routes[0]
+	if len(routes) > 1 || routes[0].conditional() {
+		handle, route = r.dispatch(routes), nil
+	}
+
+	r.tree.setHandle(key.method, key.path, handle, route)
+}
+
+// dispatch creates the handle held in the tree for routes sharing a method
+// and path. When serving a request, the router selects the candidate routes
+// itself and calls serveRoutes; this handle is used when it has been obtained
+// via Lookup.
+func (r *Router) dispatch(routes []*Route) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		candidates := candidates(routes, req)
+		if len(candidates) == 0 {
+			// as though there were no route for this method
+			handles, _, _ := r.tree.getValue(req.Method, req.URL.Path, nil)
+			r.unhandled(w, req, handles)
+			return
+		}
+
+		r.serveRoutes(w, req, ps, candidates)
+	}
+}
+
+// candidates gets the routes whose matchers are satisfied by the request.
+func candidates(routes []*Route, req *http.Request) []*Route {
+	for i, rt := range routes {
+		if !rt.matches(req) {
+			kept := append([]*Route(nil), routes[:i]...)
+			for _, rt := range routes[i+1:] {
+				if rt.matches(req) {
+					kept = append(kept, rt)
+				}
+			}
+			return kept
+		}
+	}
+	return routes
+}
+
+// serveRoutes serves the request by the candidate route whose representation
+// is the most acceptable to the client.
+func (r *Router) serveRoutes(w http.ResponseWriter, req *http.Request, ps Params, candidates []*Route) {
+	for _, rt := range candidates {
+		if len(rt.produces) > 0 {
+			w.Header().Add("Vary", "Accept")
+			break
+		}
+	}
+
+	rt := selectByAccept(candidates, req.Header.Values("Accept"))
+	if rt == nil {
+		r.notAcceptable(w, req)
+		return
+	}
+
+	if r.hooked() {
+		req = r.matched(req, rt, ps)
+	}
+
+	rt.serve(w, req, ps)
+}
+
+// Routes lists the routes known to the router, sorted by path and then by
+// method. Routes that share a method and path are listed in the order they
+// were registered. CONNECT routes are not included.
+//
+// This is intended for debugging, diagnostics and documentation.
+func (r *Router) Routes() []*Route {
+	var list []*Route
+	for _, routes := range r.routes {
+		list = append(list, routes...)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].path != list[j].path {
+			return list[i].path < list[j].path
+		}
+		return list[i].method < list[j].method
+	})
+	return list
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// RouteInfo describes the route that matched a request.
+type RouteInfo struct {
+	// Method is the method of the matched route. Note that this is GET for
+	// HEAD requests that are served by a GET route.
+	Method string
+	// Pattern is the path pattern of the matched route, e.g. "/users/:id".
+	Pattern string
+	// Name is the name of the route, if it has one (see Route.Named).
+	Name string
+	// Metadata holds the route's metadata, if any (see Route.WithMeta).
+	// It is shared by all requests and must not be altered.
+	Metadata map[string]any
+	// Params holds the values of the path parameters.
+	Params Params
+}
+
+// private type used for unique context keying
+type routeInfoKey struct{}
+
+// WithRouteInfo adds the route information into the context. A modified
+// context is returned.
+func WithRouteInfo(parent context.Context, info *RouteInfo) context.Context {
+	return context.WithValue(parent, routeInfoKey{}, info)
+}
+
+// RouteFromContext gets the route information from a request context, or
+// returns nil if none is present.
+//
+// The router stores the route information for all routes that are registered
+// using http.Handler, including via Handler, HandlerFunc, HandlerAll,
+// SubRouter and ServeFiles. For routes registered with a Handle function,
+// Router.SaveRouteInfo must be enabled.
+func RouteFromContext(ctx context.Context) *RouteInfo {
+	info, _ := ctx.Value(routeInfoKey{}).(*RouteInfo)
+	return info
+}
+
+// routeContext holds both the route information and the parameters, so that
+// a single context is enough for both.
+type routeContext struct {
+	context.Context
+	info   RouteInfo
+	params any // Params, including those of any enclosing routers
+}
+
+func (rc *routeContext) Value(key any) any {
+	switch key {
+	case routeInfoKey{}:
+		return &rc.info
+	case ParamsKey:
+		if rc.params != nil {
+			return rc.params
+		}
+	}
+	return rc.Context.Value(key)
+}
+
+// withContext adds the route information and parameters into the request
+// context. A modified request is returned.
+func (rt *Route) withContext(req *http.Request, ps Params) *http.Request {
+	parent := req.Context()
+
+	rc := &routeContext{Context: parent, info: rt.info}
+	rc.info.Params = ps
+
+	if len(ps) > 0 {
+		if existing, exists := parent.Value(ParamsKey).(Params); exists {
+			rc.params = append(existing[:len(existing):len(existing)], ps...)
+		} else {
+			rc.params = ps
+		}
+	}
+
+	return req.WithContext(rc)
+}
+
+// hooked is true if the router needs to be told when a route has matched.
+func (r *Router) hooked() bool {
+	return r.OnMatch != nil || r.SaveRouteInfo || r.chain != nil
+}
+
+// matched notifies the OnMatch hook, if any, and stores the route information
+// in the request context if SaveRouteInfo is enabled. It also records the
+// route for any middleware that uses CaptureRoute.
+func (r *Router) matched(req *http.Request, rt *Route, ps Params) *http.Request {
+	if r.chain != nil {
+		captureRoute(req, rt, ps)
+	}
+
+	if r.OnMatch != nil {
+		info := rt.info
+		info.Params = ps
+		r.OnMatch(req, &info)
+	}
+
+	if r.SaveRouteInfo && !rt.contextual {
+		req = rt.withContext(req, ps)
+	}
+
+	return req
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_OnMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var hooked []RouteInfo
+	router := New()
+	router.OnMatch = func(req *http.Request, info *RouteInfo) {
+		hooked = append(hooked, *info)
+	}
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		g.Expect(RouteFromContext(r.Context())).To(BeNil())
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	g.Expect(hooked).To(Equal([]RouteInfo{
+		{Method: http.MethodGet, Pattern: "/users/:id", Params: Params{{"id", "1"}}},
+	}))
+}
+
+func TestRouter_SaveRouteInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw *RouteInfo
+	router := New()
+	router.SaveRouteInfo = true
+	router.GET("/files/*filepath", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = RouteFromContext(r.Context())
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/files/a/b", nil))
+
+	g.Expect(saw).To(Equal(&RouteInfo{Method: http.MethodGet, Pattern: "/files/*filepath", Params: Params{{"filepath", "/a/b"}}}))
+}
+
+func TestRouteFromContext_absent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(RouteFromContext(context.Background())).To(BeNil())
+}
+
+func TestRouter_Handler_RouteInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw *RouteInfo
+	router := New()
+	router.HandlerFunc(http.MethodPut, "/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		saw = RouteFromContext(r.Context())
+		g.Expect(ParamsFromContext(r.Context())).To(Equal(Params{{"id", "7"}}))
+	}).Named("updateUser").WithMeta("auth", "admin")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/users/7", nil))
+
+	g.Expect(saw).To(Equal(&RouteInfo{
+		Method:   http.MethodPut,
+		Pattern:  "/users/:id",
+		Name:     "updateUser",
+		Metadata: map[string]any{"auth": "admin"},
+		Params:   Params{{"id", "7"}},
+	}))
+}
+
+func TestRouter_SubRouter_RouteInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var outer, inner *RouteInfo
+	var params Params
+
+	child := New()
+	child.HandlerFunc(http.MethodGet, "/items/:item", func(w http.ResponseWriter, r *http.Request) {
+		inner = RouteFromContext(r.Context())
+		params = ParamsFromContext(r.Context())
+	})
+
+	parent := New()
+	parent.SubRouter("/shops/:shop/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outer = RouteFromContext(r.Context())
+		child.ServeHTTP(w, r)
+	}), http.MethodGet)
+
+	parent.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/shops/s1/items/i2", nil))
+
+	g.Expect(outer.Pattern).To(Equal("/shops/:shop/*filepath"))
+	g.Expect(outer.Params).To(Equal(Params{{"shop", "s1"}, {"filepath", "/items/i2"}}))
+	g.Expect(inner.Pattern).To(Equal("/items/:item"))
+	g.Expect(inner.Params).To(Equal(Params{{"item", "i2"}}))
+	g.Expect(params).To(Equal(Params{{"shop", "s1"}, {"filepath", "/items/i2"}, {"item", "i2"}}))
+}
+
+func TestRouter_ServeFiles_RouteInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw *RouteInfo
+	router := New()
+	router.OnMatch = func(req *http.Request, info *RouteInfo) {
+		saw = info
+	}
+	router.ServeFiles("/static/*filepath", &mockFileSystem{}).Route().Named("static")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/static/x.txt", nil))
+
+	g.Expect(saw).To(Equal(&RouteInfo{
+		Method:  http.MethodGet,
+		Pattern: "/static/*filepath",
+		Name:    "static",
+		Params:  Params{{"filepath", "/x.txt"}},
+	}))
+}
+
+func TestRouter_Variant_RouteInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw *RouteInfo
+	handle := func(w http.ResponseWriter, r *http.Request, ps Params) {
+		saw = RouteFromContext(r.Context())
+	}
+
+	router := New()
+	router.SaveRouteInfo = true
+	router.GET("/doc", handle).Produces("application/json").Named("json")
+	router.GET("/doc", handle).Produces("text/html").Named("html")
+
+	req := httptest.NewRequest(http.MethodGet, "/doc", nil)
+	req.Header.Set("Accept", "text/html")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	g.Expect(saw.Name).To(Equal("html"))
+}
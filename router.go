@@ -5,47 +5,72 @@
 package httprouter
 
 import (
+	"context"
 	"net/http"
 	"strings"
 )
 
+// recv recovers from panics, passing them to the PanicHandler.
 func (r *Router) recv(w http.ResponseWriter, req *http.Request) {
 	if rcv := recover(); rcv != nil {
+		if rcv == http.ErrAbortHandler {
+			// net/http uses this deliberately to abort the response
+			panic(rcv)
+		}
 		r.PanicHandler(w, req, rcv)
 	}
 }
 
-func (r *Router) allowed(path, reqMethod string) (allow string) {
-	allowed := make([]string, 0, 9)
+// committable wraps the writer, unless it already reveals whether the response
+// has been committed, so that the PanicHandler can tell (see Recovery.Install).
+func committable(w http.ResponseWriter) http.ResponseWriter {
+	if _, ok := w.(interface{ Committed() bool }); ok {
+		return w
+	}
+	return &StatusWriter{ResponseWriter: w}
+}
 
+func (r *Router) allowed(path, reqMethod string) (allow string) {
 	if path == "*" { // server-wide
 		// empty method is used for internal calls to refresh the cache
 		if reqMethod == "" {
-			for method := range r.trees {
-				if method == http.MethodOptions {
-					continue
+			allowed := make([]string, 0, 9)
+			for _, method := range r.methods {
+				if method != http.MethodOptions {
+					allowed = append(allowed, method)
 				}
-				// Add request method to list of allowed methods
-				allowed = append(allowed, method)
 			}
-		} else {
-			return r.globalAllowed
+			return joinAllowed(allowed)
 		}
-	} else { // specific path
-		for method := range r.trees {
-			// Skip the requested method - we already tried this one
-			if method == reqMethod || method == http.MethodOptions {
-				continue
-			}
+		return r.globalAllowed
+	}
 
-			handle, _, _ := r.trees[method].getValue(path, nil)
-			if handle != nil {
-				// Add request method to list of allowed methods
-				allowed = append(allowed, method)
-			}
+	// specific path
+	if r.tree == nil {
+		return ""
+	}
+	handles, _, _ := r.tree.getValue(reqMethod, path, nil)
+	return handles.allowed(reqMethod)
+}
+
+// allowed lists the methods in the table, apart from the requested method
+// (which has already been tried), for use in the "Allow" header.
+func (mh methodHandles) allowed(reqMethod string) string {
+	allowed := make([]string, 0, 9)
+
+	for i := range mh {
+		// Skip the requested method - we already tried this one
+		if mh[i].method == reqMethod || mh[i].method == http.MethodOptions {
+			continue
 		}
+		// Add request method to list of allowed methods
+		allowed = append(allowed, mh[i].method)
 	}
 
+	return joinAllowed(allowed)
+}
+
+func joinAllowed(allowed []string) string {
 	if len(allowed) > 0 {
 		// Add request method to list of allowed methods
 		allowed = append(allowed, http.MethodOptions)
@@ -63,56 +88,130 @@ func (r *Router) allowed(path, reqMethod string) (allow string) {
 		return strings.Join(allowed, ", ")
 	}
 
-	return allow
+	return ""
 }
 
 // serveHTTP attempts to serve the request if a route match is found.
-func (r *Router) serveHTTP(w http.ResponseWriter, req *http.Request, method string) bool {
+// The handles registered for the path are returned so that the allowed
+// methods are known without a further traversal of the tree.
+func (r *Router) serveHTTP(w http.ResponseWriter, req *http.Request) (methodHandles, bool) {
+	if r.tree == nil {
+		return nil, false
+	}
+
+	method := req.Method
+	path := req.URL.Path
+
+	handles, m, ps, tsr := r.tree.lookup(method, path, r.getParams)
+	if m == nil {
+		r.putParams(ps)
+		return handles, r.redirect(w, req, tsr)
+	}
+
+	if m.keys != nil {
+		m.rename(ps)
+	}
+
+	// Where routes share the method and path, those that the request
+	// matches are found before it is observed as handled.
+	var routes []*Route
+	if m.route == nil {
+		if routes = candidates(r.routes[routeKey{method: m.method, path: m.path}], req); len(routes) == 0 {
+			// as though there were no route for the method
+			r.putParams(ps)
+			return handles, false
+		}
+	}
+
+	r.handle(w, req, m, ps, routes)
+	return handles, true
+}
+
+// handle serves the request by the handle that matched it, or else by one of
+// the candidate routes, if there are any.
+func (r *Router) handle(w http.ResponseWriter, req *http.Request, m *methodHandle, ps *Params, routes []*Route) {
 	if r.PanicHandler != nil {
+		if r.tracksCommit {
+			w = committable(w)
+		}
 		defer r.recv(w, req)
 	}
 
+	if r.Observer != nil || r.hooked() {
+		req = r.found(w, req, m, ps)
+	}
+
+	// For HEAD requests, if no HEAD handler had been set up, the equivalent
+	// GET handler is used as if this had been a GET request. The response
+	// content will of course be empty.
+	handle := m.handle
+	if routes != nil {
+		handle = func(w http.ResponseWriter, req *http.Request, ps Params) {
+			r.serveRoutes(w, req, ps, routes)
+		}
+	}
+	if req.Method == http.MethodHead && r.HeadContentLength && m.method != http.MethodHead {
+		handle = headViaGet(handle)
+	}
+	if ps != nil {
+		handle(w, req, *ps)
+		r.putParams(ps)
+	} else {
+		handle(w, req, nil)
+	}
+}
+
+// found reports the match to the Observer and to the hooks (see hooked).
+func (r *Router) found(w http.ResponseWriter, req *http.Request, m *methodHandle, ps *Params) *http.Request {
+	r.observe(w, req, m.path, Handled)
+	if m.route == nil || !r.hooked() {
+		return req
+	}
+	if ps != nil {
+		return r.matched(req, m.route, *ps)
+	}
+	return r.matched(req, m.route, nil)
+}
+
+// redirect redirects a request that no route matches to the path with or
+// without a trailing slash, or to the case-corrected path, if enabled and
+// there is a route for that path.
+func (r *Router) redirect(w http.ResponseWriter, req *http.Request, tsr bool) bool {
+	method := req.Method
 	path := req.URL.Path
 
-	if root := r.trees[method]; root != nil {
-		if handle, ps, tsr := root.getValue(path, r.getParams); handle != nil {
-			if ps != nil {
-				handle(w, req, *ps)
-				r.putParams(ps)
+	if method != http.MethodConnect && path != "/" {
+		// Moved Permanently, request with GET method
+		code := http.StatusMovedPermanently
+		if method != http.MethodGet && method != http.MethodHead {
+			// Permanent Redirect, request with same method
+			code = http.StatusPermanentRedirect
+		}
+
+		if tsr && r.RedirectTrailingSlash {
+			if len(path) > 1 && path[len(path)-1] == '/' {
+				req.URL.Path = path[:len(path)-1]
 			} else {
-				handle(w, req, nil)
+				req.URL.Path = path + "/"
 			}
+			r.observe(w, req, "", Redirected)
+			http.Redirect(w, req, req.URL.String(), code)
 			return true
-		} else if method != http.MethodConnect && path != "/" {
-			// Moved Permanently, request with GET method
-			code := http.StatusMovedPermanently
-			if method != http.MethodGet {
-				// Permanent Redirect, request with same method
-				code = http.StatusPermanentRedirect
-			}
+		}
 
-			if tsr && r.RedirectTrailingSlash {
-				if len(path) > 1 && path[len(path)-1] == '/' {
-					req.URL.Path = path[:len(path)-1]
-				} else {
-					req.URL.Path = path + "/"
-				}
+		// Try to fix the request path
+		if r.RedirectFixedPath {
+			fixedPath, found := r.tree.findCaseInsensitivePath(
+				method,
+				CleanPath(path),
+				r.RedirectTrailingSlash,
+			)
+			if found {
+				req.URL.Path = fixedPath
+				r.observe(w, req, "", Redirected)
 				http.Redirect(w, req, req.URL.String(), code)
 				return true
 			}
-
-			// Try to fix the request path
-			if r.RedirectFixedPath {
-				fixedPath, found := root.findCaseInsensitivePath(
-					CleanPath(path),
-					r.RedirectTrailingSlash,
-				)
-				if found {
-					req.URL.Path = fixedPath
-					http.Redirect(w, req, req.URL.String(), code)
-					return true
-				}
-			}
 		}
 	}
 
@@ -120,15 +219,59 @@ func (r *Router) serveHTTP(w http.ResponseWriter, req *http.Request, method stri
 }
 
 // ServeHTTP makes the router implement the http.Handler interface.
+// Requests that need no more than a route are served directly, keeping the
+// calls between the server and the handle to a minimum.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	if r.serveHTTP(w, req, req.Method) {
+	if r.chain != nil {
+		r.chain.ServeHTTP(w, req)
+	} else if r.Observer != nil {
+		r.serveObserved(w, req)
+	} else if req.Method == http.MethodPost || req.Method == http.MethodConnect {
+		r.route(w, req)
+	} else if handles, served := r.serveHTTP(w, req); !served {
+		r.unhandled(w, req, handles)
+	}
+}
+
+// serve is the innermost handler, i.e. the router without its middleware.
+func (r *Router) serve(w http.ResponseWriter, req *http.Request) {
+	if r.Observer != nil {
+		r.serveObserved(w, req)
+	} else {
+		r.route(w, req)
+	}
+}
+
+// serveObserved serves the request, reporting it to the Observer.
+func (r *Router) serveObserved(w http.ResponseWriter, req *http.Request) {
+	ow := newObservedWriter(w, req, r.Observer, r.methods)
+	req = req.WithContext(context.WithValue(req.Context(), observedWriterKey{}, ow))
+	defer ow.end(req)
+	r.route(ow, req)
+}
+
+// route serves the request by the route it matches or else automatically.
+func (r *Router) route(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost && len(r.MethodOverrides) > 0 {
+		req = r.overrideMethod(req)
+	}
+
+	if req.Method == http.MethodConnect && len(r.connect) > 0 && r.serveCONNECT(w, req) {
 		return
 	}
 
-	// For HEAD requests, no HEAD handler had been set up, so we retry the
-	// equivalent GET handler as if this had been a GET request. The response
-	// content will of course be empty.
-	if req.Method == http.MethodHead && r.serveHTTP(w, req, http.MethodGet) {
+	if handles, served := r.serveHTTP(w, req); !served {
+		r.unhandled(w, req, handles)
+	}
+}
+
+// unhandled answers requests that no route has handled, using the handles
+// registered for the path to decide whether to give an automatic OPTIONS
+// reply, a 405 or a 404.
+func (r *Router) unhandled(w http.ResponseWriter, req *http.Request, handles methodHandles) {
+	if req.Method == http.MethodTrace && r.HandleTRACE {
+		r.observe(w, req, "", Handled)
+		serveTRACE(w, req)
 		return
 	}
 
@@ -136,7 +279,12 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	if req.Method == http.MethodOptions && r.HandleOPTIONS {
 		// Handle OPTIONS requests
-		if allow := r.allowed(path, http.MethodOptions); allow != "" {
+		allow := r.globalAllowed
+		if path != "*" {
+			allow = r.applicable(handles, req).allowed(http.MethodOptions)
+		}
+		if allow != "" {
+			r.observe(w, req, "", AutoOPTIONS)
 			w.Header().Set("Allow", allow)
 			if r.GlobalOPTIONS != nil {
 				r.GlobalOPTIONS.ServeHTTP(w, req)
@@ -144,10 +292,19 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			return
 		}
 	} else if r.HandleMethodNotAllowed { // Handle 405
-		if allow := r.allowed(path, req.Method); allow != "" {
+		allow := r.globalAllowed
+		if path != "*" {
+			allow = r.applicable(handles, req).allowed(req.Method)
+		}
+		if allow != "" {
+			r.observe(w, req, "", MethodNotAllowed)
 			w.Header().Set("Allow", allow)
-			if r.MethodNotAllowed != nil {
-				r.MethodNotAllowed.ServeHTTP(w, req)
+			methodNotAllowed := r.MethodNotAllowed
+			if m := r.mountedAt(path, methodNotAllowedHandler); m != nil {
+				methodNotAllowed = m.MethodNotAllowed
+			}
+			if methodNotAllowed != nil {
+				methodNotAllowed.ServeHTTP(w, req)
 			} else {
 				http.Error(w,
 					http.StatusText(http.StatusMethodNotAllowed),
@@ -158,10 +315,30 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
+	if len(r.fallbacks) > 0 {
+		r.fallBack(w, req)
+		return
+	}
+
 	// Handle 404
-	if r.NotFound != nil {
-		r.NotFound.ServeHTTP(w, req)
+	r.observe(w, req, "", NotFound)
+	r.notFound(w, req)
+}
+
+// notFound answers using the NotFound handler, if it is set. Under the prefix
+// of a mounted router, that router's NotFound handler takes precedence.
+func (r *Router) notFound(w http.ResponseWriter, req *http.Request) {
+	notFound := r.NotFound
+	if m := r.mountedAt(req.URL.Path, notFoundHandler); m != nil {
+		notFound = m.NotFound
+	}
+	if notFound != nil {
+		notFound.ServeHTTP(w, req)
 	} else {
 		http.NotFound(w, req)
 	}
 }
+
+func notFoundHandler(r *Router) http.Handler { return r.NotFound }
+
+func methodNotAllowedHandler(r *Router) http.Handler { return r.MethodNotAllowed }
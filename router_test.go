@@ -286,6 +286,41 @@ func BenchmarkAllowed(b *testing.B) {
 	})
 }
 
+func BenchmarkServeHTTP(b *testing.B) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ Params) {}
+
+	router := New()
+	router.GET("/static/about", handlerFunc)
+	router.GET("/user/:name", handlerFunc)
+	router.POST("/user/:name", handlerFunc)
+	router.DELETE("/user/:name", handlerFunc)
+
+	b.Run("Static", func(b *testing.B) {
+		r, _ := http.NewRequest(http.MethodGet, "/static/about", nil)
+		w := httptest.NewRecorder()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			router.ServeHTTP(w, r)
+		}
+	})
+	b.Run("GET", func(b *testing.B) {
+		r, _ := http.NewRequest(http.MethodGet, "/user/gopher", nil)
+		w := httptest.NewRecorder()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			router.ServeHTTP(w, r)
+		}
+	})
+	b.Run("405", func(b *testing.B) {
+		r, _ := http.NewRequest(http.MethodPut, "/user/gopher", nil)
+		w := httptest.NewRecorder()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			router.ServeHTTP(w, r)
+		}
+	})
+}
+
 func TestRouter_OPTIONS(t *testing.T) {
 	g := NewGomegaWithT(t)
 	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ Params) {}
@@ -461,6 +496,27 @@ func TestRouter_NotFound(t *testing.T) {
 	g.Expect(w.Code).To(Equal(http.StatusNotFound))
 }
 
+func TestRouter_NotFound_fixedPathOtherMethod(t *testing.T) {
+	g := NewGomegaWithT(t)
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ Params) {}
+
+	router := New()
+	router.GET("/src/*filepath", handlerFunc)
+
+	// the case-corrected path has a catch-all route, but not for POST
+	r, _ := http.NewRequest(http.MethodPost, "/SRC/a.txt", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+	g.Expect(w.Header().Get("Location")).To(BeEmpty())
+
+	r, _ = http.NewRequest(http.MethodGet, "/SRC/a.txt", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusMovedPermanently))
+	g.Expect(w.Header().Get("Location")).To(Equal("/src/a.txt"))
+}
+
 func TestRouter_PanicHandler(t *testing.T) {
 	g := NewGomegaWithT(t)
 	router := New()
@@ -561,7 +617,7 @@ func TestRouter_ListPaths(t *testing.T) {
 		router.Handle(method, extra, fakeHandler(method+" "+extra))
 	}
 
-	//printChildren(router.trees[GET], "")
+	//printChildren(router.tree, "")
 
 	all := router.ListPaths("")
 	g.Expect(len(all)).To(Equal(len(AllMethods)))
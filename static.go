@@ -0,0 +1,270 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// StaticFiles serves files from a file system. It is created by
+// Router.ServeFS and its methods allow it to be configured further, but only
+// while the router is being set up.
+type StaticFiles struct {
+	fsys  fs.FS
+	route *Route
+
+	fallback    string   // for single-page applications
+	spaExcluded []string // patterns for files that don't fall back
+
+	encodings    []string // for precompressed files, in order of preference
+	cacheControl []cacheRule
+	etags        bool
+	etagCache    sync.Map // of name -> etagEntry
+
+	noListings   bool
+	listing      *template.Template
+	hideDotfiles bool
+}
+
+// DefaultSPAExclusions are the patterns of files that are not served by the
+// fallback file when StaticFiles.SPA is given none.
+var DefaultSPAExclusions = []string{
+	"*.js", "*.mjs", "*.css", "*.map", "*.json", "*.wasm",
+	"*.png", "*.jpg", "*.jpeg", "*.gif", "*.svg", "*.ico", "*.webp", "*.avif",
+	"*.woff", "*.woff2", "*.ttf", "*.otf", "*.eot",
+}
+
+// ServeFS serves files from the file system, which may for example be an
+// embed.FS or the result of os.DirFS. Files in sub-directories are served
+// too, and directories are served using their index.html file, if present.
+//
+// The path must end with "/*filepath" (or simply "/*" is allowed in this
+// case). The file names are the remainder of the request path, e.g. if path
+// is "/static/*" and the request path is "/static/css/site.css", the file
+// "css/site.css" is served. So to serve a directory within the file system,
+// use fs.Sub first.
+//
+// Requests for files that do not exist are answered using the router's
+// NotFound handler.
+//
+// Both GET and HEAD methods are supported, but no other methods.
+func (r *Router) ServeFS(path string, fsys fs.FS) *StaticFiles {
+	if strings.HasSuffix(path, "/*") {
+		path = path + "filepath"
+	} else if !strings.HasSuffix(path, "/*filepath") {
+		panic("'" + path + "' - path must end with /* or /*filepath")
+	}
+
+	sf := &StaticFiles{fsys: fsys}
+	sf.route = r.GET(path, sf.serve)
+	sf.route.contextual = true
+	return sf
+}
+
+// Route gets the GET route that serves the files.
+func (sf *StaticFiles) Route() *Route {
+	return sf.route
+}
+
+// SPA enables single-page-application mode, in which requests for files that
+// do not exist are served the fallback file instead, e.g. "index.html", so
+// that the application can do its own routing.
+//
+// Requests for missing files that match any of the excluded patterns are
+// still answered using the router's NotFound handler, so that missing assets
+// are reported properly. The patterns use the path.Match syntax; a pattern
+// containing "/" is matched against the whole file name, e.g. "assets/*",
+// otherwise it is matched against the base name, e.g. "*.js". If no patterns
+// are given, DefaultSPAExclusions is used.
+func (sf *StaticFiles) SPA(fallback string, excluded ...string) *StaticFiles {
+	if len(excluded) == 0 {
+		excluded = DefaultSPAExclusions
+	}
+	for _, pattern := range excluded {
+		checkPattern(pattern)
+	}
+	sf.fallback = fileName(fallback)
+	sf.spaExcluded = excluded
+	return sf
+}
+
+// fallsBack is true if the missing file should be answered using the
+// fallback file.
+func (sf *StaticFiles) fallsBack(name string) bool {
+	if sf.fallback == "" {
+		return false
+	}
+	for _, pattern := range sf.spaExcluded {
+		if matchFile(pattern, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPattern panics if the pattern is malformed.
+func checkPattern(pattern string) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic("'" + pattern + "' - " + err.Error())
+	}
+}
+
+// matchFile matches a file name against a pattern. A pattern containing "/"
+// is matched against the whole name, otherwise against the base name.
+func matchFile(pattern, name string) bool {
+	subject := name
+	if !strings.Contains(pattern, "/") {
+		subject = path.Base(name)
+	}
+	matched, _ := path.Match(pattern, subject)
+	return matched
+}
+
+func (sf *StaticFiles) serve(w http.ResponseWriter, req *http.Request, ps Params) {
+	req = sf.route.withContext(req, ps)
+	name := fileName(ps.ByName("filepath"))
+
+	if sf.hideDotfiles && isDotfile(name) {
+		sf.route.router.notFound(w, req)
+		return
+	}
+
+	f, fi, err := sf.open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if sf.fallsBack(name) {
+			sf.serveFallback(w, req)
+		} else {
+			sf.route.router.notFound(w, req)
+		}
+		return
+	} else if err != nil {
+		serveError(w, err)
+		return
+	}
+	defer f.Close()
+
+	if fi.IsDir() {
+		sf.serveDir(w, req, name)
+	} else if strings.HasSuffix(req.URL.Path, "/index.html") {
+		localRedirect(w, req, "./")
+	} else {
+		sf.serveFile(w, req, name, f, fi)
+	}
+}
+
+// open opens a file and gets its information.
+func (sf *StaticFiles) open(name string) (fs.File, fs.FileInfo, error) {
+	f, err := sf.fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, fi, nil
+}
+
+func (sf *StaticFiles) serveFallback(w http.ResponseWriter, req *http.Request) {
+	f, fi, err := sf.open(sf.fallback)
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+	defer f.Close()
+
+	sf.serveFile(w, req, sf.fallback, f, fi)
+}
+
+// serveDir serves the directory's index.html file, if it has one, or else
+// a listing.
+func (sf *StaticFiles) serveDir(w http.ResponseWriter, req *http.Request, name string) {
+	if url := req.URL.Path; !strings.HasSuffix(url, "/") {
+		localRedirect(w, req, path.Base(url)+"/")
+		return
+	}
+
+	index := path.Join(name, "index.html")
+	if f, fi, err := sf.open(index); err == nil {
+		defer f.Close()
+		if !fi.IsDir() {
+			sf.serveFile(w, req, index, f, fi)
+			return
+		}
+	}
+
+	sf.serveListing(w, req, name)
+}
+
+// localRedirect redirects to a path relative to the request path, keeping
+// the query.
+func localRedirect(w http.ResponseWriter, req *http.Request, newPath string) {
+	if q := req.URL.RawQuery; q != "" {
+		newPath += "?" + q
+	}
+	w.Header().Set("Location", newPath)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+// serveError answers a request for a file that could not be opened.
+func serveError(w http.ResponseWriter, err error) {
+	if errors.Is(err, fs.ErrPermission) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	} else {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// fileName converts the path parameter to a file name, which is unrooted
+// as required by fs.FS.
+func fileName(filepath string) string {
+	name := strings.TrimPrefix(path.Clean("/"+filepath), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// httpFS adapts a http.FileSystem to fs.FS.
+type httpFS struct {
+	root http.FileSystem
+}
+
+func (h httpFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		name = ""
+	}
+
+	f, err := h.root.Open("/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return httpFile{f}, nil
+}
+
+// httpFile adapts a http.File to fs.ReadDirFile, retaining its Seek method.
+type httpFile struct {
+	http.File
+}
+
+func (f httpFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	infos, err := f.Readdir(n)
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	return entries, err
+}
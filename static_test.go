@@ -0,0 +1,128 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+var testFS = fstest.MapFS{
+	"index.html":         {Data: []byte("<p>home</p>")},
+	"css/site.css":       {Data: []byte("body{}")},
+	"docs/index.html":    {Data: []byte("<p>docs</p>")},
+	"docs/guide/a.txt":   {Data: []byte("guide a")},
+	"images/logo.svg":    {Data: []byte("<svg/>")},
+	"images/.secret.txt": {Data: []byte("shh")},
+}
+
+func serveStatic(router *Router, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRouter_ServeFS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	sf := router.ServeFS("/static/*", testFS)
+
+	g.Expect(sf.Route().Path()).To(Equal("/static/*filepath"))
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{method: http.MethodGet, path: "/static/css/site.css", code: 200, body: "body{}"},
+		{method: http.MethodGet, path: "/static/docs/guide/a.txt", code: 200, body: "guide a"},
+		{method: http.MethodGet, path: "/static/", code: 200, body: "<p>home</p>"},
+		{method: http.MethodGet, path: "/static/docs/", code: 200, body: "<p>docs</p>"},
+		{method: http.MethodGet, path: "/static/docs", code: 301},
+		{method: http.MethodGet, path: "/static/nope.css", code: 410},
+		{method: http.MethodGet, path: "/static/css/../nope.css", code: 410},
+		{method: http.MethodHead, path: "/static/css/site.css", code: 200},
+		{method: http.MethodPost, path: "/static/css/site.css", code: 405},
+	}
+
+	for _, c := range cases {
+		w := serveStatic(router, c.method, c.path)
+		g.Expect(w.Code).To(Equal(c.code), c.method+" "+c.path)
+		if c.body != "" {
+			g.Expect(w.Body.String()).To(Equal(c.body), c.path)
+		}
+	}
+
+	g.Expect(serveStatic(router, http.MethodGet, "/static/docs").Header().Get("Location")).To(Equal("docs/"))
+	g.Expect(serveStatic(router, http.MethodGet, "/static/css/site.css").Header().Get("Content-Type")).To(Equal("text/css; charset=utf-8"))
+}
+
+func TestRouter_ServeFS_filepath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var info *RouteInfo
+	router := New()
+	router.ServeFS("/assets/*filepath", testFS)
+	router.OnMatch = func(req *http.Request, ri *RouteInfo) { info = ri }
+
+	w := serveStatic(router, http.MethodGet, "/assets/images/logo.svg")
+
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(info.Pattern).To(Equal("/assets/*filepath"))
+	g.Expect(func() { router.ServeFS("/bad/*name", testFS) }).To(Panic())
+}
+
+func TestFileName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(fileName("")).To(Equal("."))
+	g.Expect(fileName("/")).To(Equal("."))
+	g.Expect(fileName("/a/b.txt")).To(Equal("a/b.txt"))
+	g.Expect(fileName("/a/../../b.txt")).To(Equal("b.txt"))
+	g.Expect(fileName("/a/")).To(Equal("a"))
+}
+
+func TestStaticFiles_SPA(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/app/*filepath", testFS).SPA("index.html")
+	router.ServeFS("/custom/*", testFS).SPA("/docs/index.html", "docs/guide/*", "*.txt")
+
+	cases := []struct {
+		path string
+		code int
+		body string
+	}{
+		{path: "/app/css/site.css", code: 200},
+		{path: "/app/users/42", code: 200, body: "<p>home</p>"},
+		{path: "/app/users/42/", code: 200, body: "<p>home</p>"},
+		{path: "/app/css/missing.css", code: 404},
+		{path: "/app/main.js", code: 404},
+		{path: "/app/img/logo.PNG.png", code: 404},
+		{path: "/custom/settings", code: 200, body: "<p>docs</p>"},
+		{path: "/custom/settings.js", code: 200, body: "<p>docs</p>"},
+		{path: "/custom/docs/guide/b", code: 404},
+		{path: "/custom/notes.txt", code: 404},
+	}
+
+	for _, c := range cases {
+		w := serveStatic(router, http.MethodGet, c.path)
+		g.Expect(w.Code).To(Equal(c.code), c.path)
+		if c.body != "" {
+			g.Expect(w.Body.String()).To(Equal(c.body), c.path)
+			g.Expect(w.Header().Get("Content-Type")).To(Equal("text/html; charset=utf-8"), c.path)
+		}
+	}
+
+	g.Expect(func() { router.ServeFS("/bad/*", testFS).SPA("index.html", "[") }).To(Panic())
+}
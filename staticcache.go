@@ -0,0 +1,191 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+)
+
+// encodingSuffixes maps content codings to the file name suffixes of
+// precompressed files.
+var encodingSuffixes = map[string]string{
+	"br":   ".br",
+	"zstd": ".zst",
+	"gzip": ".gz",
+}
+
+// Precompressed enables serving precompressed variants of files, e.g.
+// "site.css.br" or "site.css.gz" instead of "site.css", when the client
+// accepts the encoding according to its Accept-Encoding header. The encodings
+// are listed in order of preference; the supported encodings are "br",
+// "zstd" and "gzip". If none are given, "br" and "gzip" are used.
+//
+// The response has the Content-Type of the original file, and Vary includes
+// Accept-Encoding.
+func (sf *StaticFiles) Precompressed(encodings ...string) *StaticFiles {
+	if len(encodings) == 0 {
+		encodings = []string{"br", "gzip"}
+	}
+	for _, enc := range encodings {
+		if encodingSuffixes[enc] == "" {
+			panic("'" + enc + "' - unsupported encoding")
+		}
+	}
+	sf.encodings = encodings
+	return sf
+}
+
+type cacheRule struct {
+	pattern, value string
+}
+
+// CacheControl sets the Cache-Control header for files matching the pattern,
+// e.g.
+//
+//	sf.CacheControl("assets/*", "public, max-age=31536000, immutable")
+//	sf.CacheControl("*.html", "no-cache")
+//
+// Patterns use the same syntax as StaticFiles.SPA. They are tried in the
+// order they were added, and the first that matches is used.
+func (sf *StaticFiles) CacheControl(pattern, value string) *StaticFiles {
+	checkPattern(pattern)
+	sf.cacheControl = append(sf.cacheControl, cacheRule{pattern: pattern, value: value})
+	return sf
+}
+
+// ETags enables strong ETags computed from the content of each file. The
+// hashes are computed when each file is first served, and are recomputed
+// if its size or modification time changes.
+func (sf *StaticFiles) ETags() *StaticFiles {
+	sf.etags = true
+	return sf
+}
+
+// serveFile serves a file that has been opened and is not a directory.
+func (sf *StaticFiles) serveFile(w http.ResponseWriter, req *http.Request, name string, f fs.File, fi fs.FileInfo) {
+	h := w.Header()
+
+	for _, rule := range sf.cacheControl {
+		if matchFile(rule.pattern, name) {
+			h.Set("Cache-Control", rule.value)
+			break
+		}
+	}
+
+	served := name
+	if len(sf.encodings) > 0 {
+		h.Add("Vary", "Accept-Encoding")
+		if enc, variant := sf.precompressed(req, name); variant != "" {
+			if vf, vfi, err := sf.open(variant); err == nil {
+				defer vf.Close()
+				ctype := mime.TypeByExtension(path.Ext(name))
+				if ctype == "" {
+					ctype = "application/octet-stream" // sniffing the variant would be wrong
+				}
+				h.Set("Content-Type", ctype)
+				h.Set("Content-Encoding", enc)
+				served, f, fi = variant, vf, vfi
+			}
+		}
+	}
+
+	if sf.etags {
+		if tag := sf.etag(served, fi); tag != "" {
+			h.Set("ETag", tag)
+		}
+	}
+
+	if rs, ok := f.(io.ReadSeeker); ok {
+		http.ServeContent(w, req, name, fi.ModTime(), sizedFile{rs, fi.Size()})
+	} else {
+		http.ServeFileFS(w, req, sf.fsys, served)
+	}
+}
+
+// sizedFile gives http.ServeContent the size of the file from its FileInfo,
+// as http.FileServer does, rather than by seeking to the end.
+type sizedFile struct {
+	io.ReadSeeker
+	size int64
+}
+
+func (f sizedFile) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekEnd && offset == 0 {
+		return f.size, nil // ServeContent then seeks to the start
+	}
+	return f.ReadSeeker.Seek(offset, whence)
+}
+
+// precompressed finds the most preferred precompressed variant of the file
+// that the client accepts, if any.
+func (sf *StaticFiles) precompressed(req *http.Request, name string) (encoding, variant string) {
+	ranges := parseAccept(req.Header.Values("Accept-Encoding"))
+	if len(ranges) == 0 {
+		return "", ""
+	}
+
+	for _, enc := range sf.encodings {
+		if encodingQuality(enc, ranges) <= 0 {
+			continue
+		}
+		variant = name + encodingSuffixes[enc]
+		if fi, err := fs.Stat(sf.fsys, variant); err == nil && !fi.IsDir() {
+			return enc, variant
+		}
+	}
+	return "", ""
+}
+
+// encodingQuality gets the q-value of a content coding from Accept-Encoding.
+func encodingQuality(encoding string, ranges []mediaRange) float64 {
+	q := 0.0
+	for _, r := range ranges {
+		if r.value == encoding {
+			return r.q
+		} else if r.value == "*" {
+			q = r.q
+		}
+	}
+	return q
+}
+
+type etagEntry struct {
+	size    int64
+	modTime time.Time
+	tag     string
+}
+
+// etag gets the ETag of a file, computing it if necessary. It returns a blank
+// string if the file cannot be read.
+func (sf *StaticFiles) etag(name string, fi fs.FileInfo) string {
+	if cached, ok := sf.etagCache.Load(name); ok {
+		e := cached.(etagEntry)
+		if e.size == fi.Size() && e.modTime.Equal(fi.ModTime()) {
+			return e.tag
+		}
+	}
+
+	f, err := sf.fsys.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return ""
+	}
+
+	tag := `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	sf.etagCache.Store(name, etagEntry{size: fi.Size(), modTime: fi.ModTime(), tag: tag})
+	return tag
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var compressedFS = fstest.MapFS{
+	"app.js":          {Data: []byte("plain js")},
+	"app.js.gz":       {Data: []byte("gzip js")},
+	"app.js.br":       {Data: []byte("brotli js")},
+	"style.css":       {Data: []byte("plain css")},
+	"style.css.gz":    {Data: []byte("gzip css")},
+	"data.unknown":    {Data: []byte("plain data")},
+	"data.unknown.gz": {Data: []byte("gzip data")},
+}
+
+func TestStaticFiles_Precompressed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/s/*", compressedFS).Precompressed()
+
+	cases := []struct {
+		path, accept, encoding, body, ctype string
+	}{
+		{path: "/s/app.js", accept: "", body: "plain js", ctype: "text/javascript; charset=utf-8"},
+		{path: "/s/app.js", accept: "gzip, deflate, br", encoding: "br", body: "brotli js", ctype: "text/javascript; charset=utf-8"},
+		{path: "/s/app.js", accept: "gzip", encoding: "gzip", body: "gzip js", ctype: "text/javascript; charset=utf-8"},
+		{path: "/s/app.js", accept: "br;q=0, *", encoding: "gzip", body: "gzip js", ctype: "text/javascript; charset=utf-8"},
+		{path: "/s/app.js", accept: "identity", body: "plain js", ctype: "text/javascript; charset=utf-8"},
+		{path: "/s/style.css", accept: "br", body: "plain css", ctype: "text/css; charset=utf-8"},
+		{path: "/s/style.css", accept: "br, gzip", encoding: "gzip", body: "gzip css", ctype: "text/css; charset=utf-8"},
+		{path: "/s/data.unknown", accept: "gzip", encoding: "gzip", body: "gzip data", ctype: "application/octet-stream"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		if c.accept != "" {
+			req.Header.Set("Accept-Encoding", c.accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		info := c.path + " " + c.accept
+		g.Expect(w.Code).To(Equal(http.StatusOK), info)
+		g.Expect(w.Body.String()).To(Equal(c.body), info)
+		g.Expect(w.Header().Get("Content-Encoding")).To(Equal(c.encoding), info)
+		g.Expect(w.Header().Get("Content-Type")).To(Equal(c.ctype), info)
+		g.Expect(w.Header().Get("Vary")).To(Equal("Accept-Encoding"), info)
+	}
+
+	g.Expect(func() { router.ServeFS("/x/*", compressedFS).Precompressed("deflate") }).To(Panic())
+}
+
+func TestStaticFiles_CacheControl(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/s/*", testFS).
+		CacheControl("images/*", "public, max-age=31536000, immutable").
+		CacheControl("*.html", "no-cache").
+		CacheControl("*", "public, max-age=60")
+
+	cases := map[string]string{
+		"/s/images/logo.svg": "public, max-age=31536000, immutable",
+		"/s/docs/":           "no-cache",
+		"/s/css/site.css":    "public, max-age=60",
+	}
+
+	for path, expected := range cases {
+		w := serveStatic(router, http.MethodGet, path)
+		g.Expect(w.Code).To(Equal(http.StatusOK), path)
+		g.Expect(w.Header().Get("Cache-Control")).To(Equal(expected), path)
+	}
+
+	g.Expect(serveStatic(router, http.MethodGet, "/s/nope").Header().Get("Cache-Control")).To(BeEmpty())
+}
+
+func TestStaticFiles_ETags(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("version 1"), ModTime: time.Unix(1000, 0)},
+		"b.txt": {Data: []byte("version 1"), ModTime: time.Unix(2000, 0)},
+	}
+
+	router := New()
+	router.ServeFS("/s/*", fsys).ETags()
+
+	w := serveStatic(router, http.MethodGet, "/s/a.txt")
+	etag := w.Header().Get("ETag")
+	g.Expect(etag).To(MatchRegexp(`^"[A-Za-z0-9_-]{22}"$`))
+
+	// the same content has the same tag
+	g.Expect(serveStatic(router, http.MethodGet, "/s/b.txt").Header().Get("ETag")).To(Equal(etag))
+
+	req := httptest.NewRequest(http.MethodGet, "/s/a.txt", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	g.Expect(w.Code).To(Equal(http.StatusNotModified))
+
+	// a changed file has a new tag
+	fsys["a.txt"] = &fstest.MapFile{Data: []byte("version 2"), ModTime: time.Unix(3000, 0)}
+	g.Expect(serveStatic(router, http.MethodGet, "/s/a.txt").Header().Get("ETag")).NotTo(Equal(etag))
+}
+
+func TestStaticFiles_ETags_precompressed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.ServeFS("/s/*", compressedFS).Precompressed().ETags()
+
+	plain := serveStatic(router, http.MethodGet, "/s/app.js").Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/s/app.js", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	g.Expect(w.Header().Get("ETag")).NotTo(BeEmpty())
+	g.Expect(w.Header().Get("ETag")).NotTo(Equal(plain))
+}
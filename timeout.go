@@ -0,0 +1,207 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// WithTimeout limits how long the route's handle may take. The handle's
+// request context has a deadline, and if the handle has not started its
+// response by then, the router replies using Router.OnTimeout instead.
+//
+// Unlike http.TimeoutHandler, the response is not buffered: it is passed
+// on as soon as it is written, so streaming responses are not delayed. If
+// the deadline passes after the response has started, the response is cut
+// short. Either way, any later writes by the handle fail with
+// http.ErrHandlerTimeout.
+//
+// The handle runs in its own goroutine, with its own copy of the Params. If
+// it panics before the request has finished, the panic is passed on to the
+// router as a *HandlerPanic, which holds the stack trace of that goroutine. If
+// it panics later, it is too late to reply, so the PanicHandler is given the
+// *HandlerPanic together with a writer whose writes fail; without a
+// PanicHandler, the panic is logged using slog.Default().
+//
+// A zero duration removes the limit.
+func (rt *Route) WithTimeout(d time.Duration) *Route {
+	return rt.refine(func() { rt.timeout = d })
+}
+
+// Timeout gets the timeout of the route, or zero if it has none.
+func (rt *Route) Timeout() time.Duration {
+	return rt.timeout
+}
+
+// withTimeout wraps the handle so that it is subject to a deadline.
+func (r *Router) withTimeout(handle Handle, d time.Duration) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		ctx, cancel := context.WithTimeout(req.Context(), d)
+		defer cancel()
+		req = req.WithContext(ctx)
+
+		if len(ps) > 0 {
+			ps = append(Params(nil), ps...) // the originals are recycled
+		}
+
+		tw := &timeoutWriter{ctx: ctx, w: w, h: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+
+		go func() {
+			defer func() {
+				if rcv := recover(); rcv != nil {
+					r.passOn(tw, req, rcv, panicked)
+				}
+			}()
+			handle(tw, req, ps)
+			close(done)
+		}()
+
+		select {
+		case rcv := <-panicked:
+			panic(rcv)
+		case <-done:
+		case <-ctx.Done():
+		}
+
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		tw.finished = true
+
+		select {
+		case rcv := <-panicked:
+			panic(rcv) // just as the deadline passed
+		default:
+		}
+
+		if tw.wroteHeader {
+			return
+		}
+
+		if ctx.Err() == nil {
+			copyHeader(w.Header(), tw.h)
+		} else if r.OnTimeout != nil {
+			r.OnTimeout.ServeHTTP(w, req)
+		} else {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// passOn passes a panic recovered in the handle's goroutine to the request's
+// goroutine, which re-panics, unless the request has already finished. It must
+// be called by the function that recovered the panic, so that the stack trace
+// is still available.
+func (r *Router) passOn(tw *timeoutWriter, req *http.Request, rcv interface{}, panicked chan<- interface{}) {
+	if rcv != http.ErrAbortHandler {
+		rcv = &HandlerPanic{Value: rcv, Stack: debug.Stack()}
+	}
+
+	tw.mu.Lock()
+	late := tw.finished
+	if !late {
+		panicked <- rcv
+	}
+	tw.mu.Unlock()
+
+	switch {
+	case !late || rcv == http.ErrAbortHandler:
+	case r.PanicHandler != nil:
+		r.PanicHandler(tw, req, rcv)
+	default:
+		hp := rcv.(*HandlerPanic)
+		slog.Default().LogAttrs(context.Background(), slog.LevelError, "panic after timeout",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.String("panic", hp.String()),
+			slog.String("stack", string(hp.Stack)),
+		)
+	}
+}
+
+// timeoutWriter passes the response through to the underlying writer until
+// the request has finished, after which all writes fail. The response cannot
+// be started once the deadline has passed. The handle has its own header map,
+// which is copied when the header is written, so that it cannot interfere with
+// a timeout reply.
+type timeoutWriter struct {
+	ctx         context.Context
+	w           http.ResponseWriter
+	h           http.Header
+	mu          sync.Mutex
+	wroteHeader bool
+	finished    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.writable() {
+		tw.writeHeader(code)
+	}
+}
+
+// writable is true until the request has finished, or until the deadline
+// has passed if the response has not been started.
+func (tw *timeoutWriter) writable() bool {
+	return !tw.finished && (tw.wroteHeader || tw.ctx.Err() == nil)
+}
+
+func (tw *timeoutWriter) writeHeader(code int) {
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		// informational responses don't commit the response
+		copyHeader(tw.w.Header(), tw.h)
+		tw.w.WriteHeader(code)
+		return
+	}
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		copyHeader(tw.w.Header(), tw.h)
+		tw.w.WriteHeader(code)
+	}
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.writable() {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	return tw.w.Write(p)
+}
+
+// Flush implements http.Flusher so that streamed responses are not delayed.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.writable() {
+		return
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
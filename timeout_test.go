@@ -0,0 +1,191 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bytes"
+	"context"
+	. "github.com/onsi/gomega"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRoute_WithTimeout_expires(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctxErr, id, writeErr := make(chan error, 1), make(chan string, 1), make(chan error, 1)
+	router := New()
+	router.GET("/slow/:id", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		<-req.Context().Done()
+		ctxErr <- req.Context().Err()
+		id <- ps.ByName("id")
+		w.Header().Set("X-Late", "yes")
+		_, err := w.Write([]byte("too late"))
+		writeErr <- err
+	}).WithTimeout(10 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Body.String()).To(Equal("Service Unavailable\n"))
+	g.Expect(<-ctxErr).To(Equal(context.DeadlineExceeded))
+	g.Expect(<-id).To(Equal("1"))
+	g.Expect(<-writeErr).To(Equal(http.ErrHandlerTimeout))
+	g.Expect(w.Header().Get("X-Late")).To(BeEmpty())
+}
+
+func TestRoute_WithTimeout_OnTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.OnTimeout = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+	})
+	router.HandlerFunc(http.MethodGet, "/slow", func(w http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}).WithTimeout(time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusGatewayTimeout))
+}
+
+func TestRoute_WithTimeout_in_time(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hasDeadline := make(chan bool, 1)
+	router := New()
+	router.GET("/fast", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		_, ok := req.Context().Deadline()
+		hasDeadline <- ok
+		w.Header().Set("Content-Type", "text/plain")
+	}).WithTimeout(time.Second)
+	router.GET("/created", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("ok"))
+	}).WithTimeout(time.Second)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("text/plain"))
+	g.Expect(<-hasDeadline).To(BeTrue())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/created", nil))
+	g.Expect(w.Code).To(Equal(http.StatusCreated))
+	g.Expect(w.Body.String()).To(Equal("ok"))
+}
+
+func TestRoute_WithTimeout_streaming(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	w := httptest.NewRecorder()
+	flushed, body := make(chan bool, 1), make(chan string, 1)
+	router := New()
+	router.GET("/stream", func(tw http.ResponseWriter, req *http.Request, ps Params) {
+		tw.Write([]byte("first"))
+		tw.(http.Flusher).Flush()
+		// the response is passed on without waiting for the handle to finish
+		flushed <- w.Flushed
+		body <- w.Body.String()
+		<-req.Context().Done()
+	}).WithTimeout(20 * time.Millisecond)
+
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	g.Expect(<-flushed).To(BeTrue())
+	g.Expect(<-body).To(Equal("first"))
+
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(Equal("first"))
+}
+
+func TestRoute_WithTimeout_panic(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var recovered interface{}
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, req *http.Request, rcv interface{}) {
+		recovered = rcv
+	}
+	router.GET("/boom", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		panic("boom")
+	}).WithTimeout(time.Second)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	g.Expect(recovered).To(BeAssignableToTypeOf(&HandlerPanic{}))
+	g.Expect(recovered.(*HandlerPanic).Value).To(Equal("boom"))
+}
+
+func TestRoute_WithTimeout_panic_stack(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	router := New()
+	(&Recovery{Logger: slog.New(slog.NewJSONHandler(buf, nil))}).Install(router)
+	router.GET("/boom", panickingHandle).WithTimeout(time.Second)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	g.Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	g.Expect(buf.String()).To(ContainSubstring(`"panic":"boom"`))
+	// the stack is that of the handle's goroutine
+	g.Expect(buf.String()).To(ContainSubstring("panickingHandle"))
+}
+
+func TestRoute_WithTimeout_late_panic(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recovered := make(chan interface{}, 1)
+	release := make(chan struct{})
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, req *http.Request, rcv interface{}) {
+		recovered <- rcv
+	}
+	router.GET("/late", func(w http.ResponseWriter, req *http.Request, ps Params) {
+		<-release
+		panic("late")
+	}).WithTimeout(time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/late", nil))
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+
+	close(release)
+	var rcv interface{}
+	g.Eventually(recovered).Should(Receive(&rcv))
+	g.Expect(rcv.(*HandlerPanic).Value).To(Equal("late"))
+}
+
+func panickingHandle(http.ResponseWriter, *http.Request, Params) {
+	panic("boom")
+}
+
+func TestRouter_Routes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.POST("/b", noop).WithTimeout(time.Second)
+	router.GET("/b", noop)
+	router.GET("/a", noop).Produces("text/html")
+	router.GET("/a", noop).Produces("application/json")
+
+	var listed []string
+	for _, rt := range router.Routes() {
+		listed = append(listed, rt.Method()+" "+rt.Path()+" "+rt.Timeout().String())
+	}
+
+	g.Expect(listed).To(Equal([]string{"GET /a 0s", "GET /a 0s", "GET /b 0s", "POST /b 1s"}))
+	g.Expect(router.Routes()[0].produces).To(Equal([]string{"text/html"}))
+}
+
+func noop(http.ResponseWriter, *http.Request, Params) {}
@@ -0,0 +1,39 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"net/http/httputil"
+)
+
+// TraceStrippedHeaders lists the request headers that are never echoed in the
+// response to a TRACE request, because they may carry credentials.
+// You can change it if you need a different set of headers.
+var TraceStrippedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Csrf-Token",
+}
+
+// serveTRACE echoes the request back to the client as a "message/http"
+// response, after removing any sensitive headers.
+func serveTRACE(w http.ResponseWriter, req *http.Request) {
+	echo := req.Clone(req.Context())
+	for _, h := range TraceStrippedHeaders {
+		echo.Header.Del(h)
+	}
+
+	dump, err := httputil.DumpRequest(echo, false)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "message/http")
+	w.Write(dump)
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_TRACE(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/path", func(w http.ResponseWriter, r *http.Request, ps Params) {})
+
+	// disabled by default
+	r := httptest.NewRequest(http.MethodTrace, "/path", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+
+	router.HandleTRACE = true
+
+	r = httptest.NewRequest(http.MethodTrace, "/path", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Cookie", "session=secret")
+	r.Header.Set("X-Custom", "visible")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("message/http"))
+
+	body := w.Body.String()
+	g.Expect(strings.HasPrefix(body, "TRACE /path HTTP/1.1\r\n")).To(BeTrue(), body)
+	g.Expect(body).To(ContainSubstring("X-Custom: visible"))
+	g.Expect(body).NotTo(ContainSubstring("secret"))
+
+	// explicit handlers take priority
+	custom := 0
+	router.Handle(http.MethodTrace, "/path", func(w http.ResponseWriter, r *http.Request, ps Params) {
+		custom++
+	})
+	r = httptest.NewRequest(http.MethodTrace, "/path", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(custom).To(Equal(1))
+	g.Expect(w.Body.Len()).To(Equal(0))
+}
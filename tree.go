@@ -6,7 +6,7 @@ package httprouter
 
 import (
 	"io"
-	"sort"
+	"net/http"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -53,6 +53,38 @@ func findWildcard(path string) (wilcard string, i int, valid bool) {
 	return "", -1, false
 }
 
+// checkWildcards describes the first malformed wildcard in the path, or
+// returns "" if they are all well formed. These are the checks that
+// insertChild makes, made in advance so that a malformed path never leaves
+// part of itself in the tree.
+func checkWildcards(path string) string {
+	for offset := 0; ; {
+		wildcard, i, valid := findWildcard(path[offset:])
+		if i < 0 {
+			return ""
+		}
+		i += offset
+
+		if !valid {
+			return "only one wildcard per path segment is allowed, has: '" +
+				wildcard + "' in path '" + path + "'"
+		}
+		if len(wildcard) < 2 {
+			return "wildcards must be named with a non-empty name in path '" + path + "'"
+		}
+		if wildcard[0] == '*' {
+			if i+len(wildcard) != len(path) {
+				return "catch-all routes are only allowed at the end of the path in path '" + path + "'"
+			}
+			if i == 0 || path[i-1] != '/' {
+				return "no / before catch-all in path '" + path + "'"
+			}
+		}
+
+		offset = i + len(wildcard)
+	}
+}
+
 func countParams(path string) uint16 {
 	var n uint
 	for i := range []byte(path) {
@@ -73,6 +105,84 @@ const (
 	catchAll
 )
 
+// methodHandle pairs a request method with the handle registered for it.
+// The path is the full path pattern with which the handle was registered.
+// The route is set by the Router when the handle belongs to a single route.
+//
+// The keys are the names of the wildcards in the path. They are set only if
+// these differ from the names held by the tree's nodes, which are those of the
+// first route to use each wildcard; routes for other methods may name the same
+// wildcard differently.
+type methodHandle struct {
+	method string
+	path   string
+	handle Handle
+	route  *Route
+	keys   []string
+}
+
+// rename gives the params the names of the handle's own wildcards, if they
+// differ from those of the tree (see keys).
+func (m *methodHandle) rename(ps *Params) {
+	for i, key := range m.keys {
+		(*ps)[i].Key = key
+	}
+}
+
+// methodHandles is the small table of handles held by each leaf node, one
+// per request method. It is expected to be short, so a linear scan is used.
+type methodHandles []methodHandle
+
+// find returns the entry for the method, or nil if there is no handle for it.
+func (mh methodHandles) find(method string) *methodHandle {
+	for i := range mh {
+		if mh[i].method == method && mh[i].handle != nil {
+			return &mh[i]
+		}
+	}
+	return nil
+}
+
+// get returns the handle registered for the method, or nil if there is none.
+func (mh methodHandles) get(method string) Handle {
+	if m := mh.find(method); m != nil {
+		return m.handle
+	}
+	return nil
+}
+
+// match returns the entry that serves requests with the method. Following IETF
+// recommendations, GET handles also serve HEAD requests unless a specific HEAD
+// handle has been registered.
+func (mh methodHandles) match(method string) *methodHandle {
+	if m := mh.find(method); m != nil || method != http.MethodHead {
+		return m
+	}
+	return mh.find(http.MethodGet)
+}
+
+// serves returns true if there is a handle that serves requests with the method.
+func (mh methodHandles) serves(method string) bool {
+	return mh.match(method) != nil
+}
+
+// has returns true if a handle is registered for the method.
+func (mh methodHandles) has(method string) bool {
+	return mh.find(method) != nil
+}
+
+// set registers the handle for the method, replacing any previous entry.
+func (mh methodHandles) set(method, path string, handle Handle, keys []string) methodHandles {
+	for i := range mh {
+		if mh[i].method == method {
+			mh[i].handle = handle
+			mh[i].keys = keys
+			return mh
+		}
+	}
+	return append(mh, methodHandle{method: method, path: path, handle: handle, keys: keys})
+}
+
 type node struct {
 	path      string
 	indices   string
@@ -80,7 +190,8 @@ type node struct {
 	nType     nodeType
 	priority  uint32
 	children  []*node
-	handle    Handle
+	handles   methodHandles
+	renamed   bool // a wildcard that some routes name differently
 }
 
 // Increments priority of the given child and reorders if necessary
@@ -106,15 +217,18 @@ func (n *node) incrementChildPrio(pos int) int {
 	return newPos
 }
 
-// addRoute adds a node with the given handle to the path.
+// addRoute adds a node with the given handle to the path, for the given method.
+// If the route conflicts with an existing route, it panics with a
+// *ConflictError, and the tree may have been altered (see tryAddRoute).
 // Not concurrency-safe!
-func (n *node) addRoute(path string, handle Handle) {
+func (n *node) addRoute(method, path string, handle Handle) {
 	fullPath := path
+	var keys []string // set if a wildcard is named differently (see methodHandle)
 	n.priority++
 
 	// Empty tree
 	if n.path == "" && n.indices == "" {
-		n.insertChild(path, fullPath, handle)
+		n.insertChild(method, path, fullPath, handle, nil)
 		n.nType = root
 		return
 	}
@@ -134,7 +248,7 @@ walk:
 				nType:     static,
 				indices:   n.indices,
 				children:  n.children,
-				handle:    n.handle,
+				handles:   n.handles,
 				priority:  n.priority - 1,
 			}
 
@@ -142,7 +256,7 @@ walk:
 			// []byte for proper unicode char conversion, see #65
 			n.indices = string([]byte{n.path[i]})
 			n.path = path[:i]
-			n.handle = nil
+			n.handles = nil
 			n.wildChild = false
 		}
 
@@ -154,12 +268,25 @@ walk:
 				n = n.children[0]
 				n.priority++
 
-				// Check if the wildcard matches
-				if len(path) >= len(n.path) && n.path == path[:len(n.path)] &&
-					// Adding a child to a catchAll is not possible
-					n.nType != catchAll &&
-					// Check for longer wildcard, e.g. :name and :names
-					(len(n.path) >= len(path) || path[len(n.path)] == '/') {
+				// Check if the wildcard matches. Adding a child to a catchAll
+				// is not possible, but adding a handle for another method is.
+				// The wildcard may be named differently, provided that the
+				// method's other routes agree with the name.
+				seg := n.wildcardSegment(path)
+				if seg != "" && seg == n.path && !n.renamed {
+					continue walk
+				}
+				i := countParams(fullPath[:len(fullPath)-len(path)])
+				var disagreeing *methodHandle
+				if seg != "" {
+					disagreeing = n.disagreeing(method, i, seg)
+				}
+				if seg != "" && disagreeing == nil {
+					if seg != n.path {
+						n.renamed = true
+						keys = wildcardKeys(fullPath)
+						path = n.path + path[len(seg):]
+					}
 					continue walk
 				} else {
 					// Wildcard conflict
@@ -167,12 +294,23 @@ walk:
 					if n.nType != catchAll {
 						pathSeg = strings.SplitN(pathSeg, "/", 2)[0]
 					}
-					prefix := fullPath[:strings.Index(fullPath, pathSeg)] + n.path
-					panic("'" + pathSeg +
-						"' in new path '" + fullPath +
-						"' conflicts with existing wildcard '" + n.path +
-						"' in existing prefix '" + prefix +
-						"'")
+					// the method's own routes name the wildcard, if they
+					// disagree with the new name
+					wildcard, existing := n.path, ""
+					if disagreeing != nil {
+						wildcard = n.path[:len(n.path)-len(wildcardKey(n.path))] + disagreeing.keyAt(i)
+						existing = disagreeing.path
+					}
+					prefix := fullPath[:strings.Index(fullPath, pathSeg)] + wildcard
+					if existing == "" {
+						existing = n.anyPattern(prefix)
+					}
+					panic(newConflict(method, fullPath, existing, pathSeg,
+						"'"+pathSeg+
+							"' in new path '"+fullPath+
+							"' conflicts with existing wildcard '"+wildcard+
+							"' in existing prefix '"+prefix+
+							"'"))
 				}
 			}
 
@@ -203,20 +341,50 @@ walk:
 				n.incrementChildPrio(len(n.indices) - 1)
 				n = child
 			}
-			n.insertChild(path, fullPath, handle)
+			n.insertChild(method, path, fullPath, handle, keys)
 			return
 		}
 
 		// Otherwise add handle to current node
-		if n.handle != nil {
-			panic("a handle is already registered for path '" + fullPath + "'")
+		if n.handles.has(method) {
+			panic(newConflict(method, fullPath, n.handles.find(method).path, "",
+				"a handle is already registered for path '"+fullPath+"'"))
 		}
-		n.handle = handle
+		n.handles = n.handles.set(method, fullPath, handle, keys)
 		return
 	}
 }
 
-func (n *node) insertChild(path, fullPath string, handle Handle) {
+// tryAddRoute is like addRoute but returns a conflict instead of panicking.
+// In that case the tree is unaffected, because the route is first added to a
+// copy of the tree; addRoute may have altered the tree, e.g. by splitting a
+// node, by the time it finds a conflict.
+func (n *node) tryAddRoute(method, path string, handle Handle) *ConflictError {
+	if conflict := n.conflicts(method, path); conflict != nil {
+		return conflict
+	}
+	n.addRoute(method, path, handle)
+	return nil
+}
+
+// conflicts finds whether adding the route would cause a conflict, by adding
+// it to a copy of the tree.
+func (n *node) conflicts(method, path string) (conflict *ConflictError) {
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			c, ok := rcv.(*ConflictError)
+			if !ok {
+				panic(rcv)
+			}
+			conflict = c
+		}
+	}()
+
+	n.clone().addRoute(method, path, validated)
+	return nil
+}
+
+func (n *node) insertChild(method, path, fullPath string, handle Handle, keys []string) {
 	for {
 		// Find prefix until first wildcard
 		wildcard, i, valid := findWildcard(path)
@@ -238,8 +406,9 @@ func (n *node) insertChild(path, fullPath string, handle Handle) {
 		// Check if this node has existing children which would be
 		// unreachable if we insert the wildcard here
 		if len(n.children) > 0 {
-			panic("wildcard segment '" + wildcard +
-				"' conflicts with existing children in path '" + fullPath + "'")
+			panic(newConflict(method, fullPath, n.anyPattern(""), wildcard,
+				"wildcard segment '"+wildcard+
+					"' conflicts with existing children in path '"+fullPath+"'"))
 		}
 
 		// param
@@ -272,7 +441,7 @@ func (n *node) insertChild(path, fullPath string, handle Handle) {
 			}
 
 			// Otherwise we're done. Insert the handle in the new leaf
-			n.handle = handle
+			n.handles = methodHandles{{method: method, path: fullPath, handle: handle, keys: keys}}
 			return
 		}
 
@@ -282,7 +451,8 @@ func (n *node) insertChild(path, fullPath string, handle Handle) {
 		}
 
 		if len(n.path) > 0 && n.path[len(n.path)-1] == '/' {
-			panic("catch-all conflicts with existing handle for the path segment root in path '" + fullPath + "'")
+			panic(newConflict(method, fullPath, n.anyPattern(""), wildcard,
+				"catch-all conflicts with existing handle for the path segment root in path '"+fullPath+"'"))
 		}
 
 		// Currently fixed width 1 for '/'
@@ -307,7 +477,7 @@ func (n *node) insertChild(path, fullPath string, handle Handle) {
 		child = &node{
 			path:     path[i:],
 			nType:    catchAll,
-			handle:   handle,
+			handles:  methodHandles{{method: method, path: fullPath, handle: handle, keys: keys}},
 			priority: 1,
 		}
 		n.children = []*node{child}
@@ -317,15 +487,126 @@ func (n *node) insertChild(path, fullPath string, handle Handle) {
 
 	// If no wildcard was found, simply insert the path and handle
 	n.path = path
-	n.handle = handle
+	n.handles = methodHandles{{method: method, path: fullPath, handle: handle, keys: keys}}
+}
+
+// wildcardSegment gets the wildcard at the start of the path if it is of the
+// same kind as the node's, i.e. a param or a catch-all, or "" otherwise.
+func (n *node) wildcardSegment(path string) string {
+	switch {
+	case n.nType == param && path[0] == ':':
+		if end := strings.IndexByte(path, '/'); end > 0 {
+			return path[:end]
+		}
+		return path
+	case n.nType == catchAll && strings.HasPrefix(path, "/*") && strings.IndexByte(path[1:], '/') < 0:
+		return path
+	}
+	return ""
+}
+
+// disagreeing finds a route of the method, at or below the wildcard node, that
+// gives the i'th wildcard (counting from zero) a different name from the
+// segment. It returns nil if there is none.
+func (n *node) disagreeing(method string, i uint16, seg string) *methodHandle {
+	for j := range n.handles {
+		if m := &n.handles[j]; m.method == method && m.keyAt(i) != wildcardKey(seg) {
+			return m
+		}
+	}
+	for _, child := range n.children {
+		if m := child.disagreeing(method, i, seg); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// keyAt gets the name of the i'th wildcard of the handle's path.
+func (m *methodHandle) keyAt(i uint16) string {
+	return wildcardKeys(m.path)[i]
+}
+
+// wildcardKeys gets the names of the wildcards in the path, which are the keys
+// of its params.
+func wildcardKeys(path string) []string {
+	var keys []string
+	for {
+		wildcard, i, _ := findWildcard(path)
+		if i < 0 {
+			return keys
+		}
+		keys = append(keys, wildcard[1:])
+		path = path[i+len(wildcard):]
+	}
+}
+
+// wildcardKey gets the name of a wildcard segment, e.g. "id" from ":id" or
+// "filepath" from "/*filepath".
+func wildcardKey(seg string) string {
+	return seg[strings.IndexAny(seg, ":*")+1:]
+}
+
+// anyPattern gets the path of a route registered at or below the node, or the
+// alternative if there is none.
+func (n *node) anyPattern(alternative string) string {
+	if len(n.handles) > 0 {
+		return n.handles[0].path
+	}
+	for _, child := range n.children {
+		if pattern := child.anyPattern(""); pattern != "" {
+			return pattern
+		}
+	}
+	return alternative
+}
+
+// clone makes a deep copy of the node and its children, for trying out
+// changes to the tree.
+func (n *node) clone() *node {
+	c := *n
+	c.handles = append(methodHandles(nil), n.handles...)
+	c.children = make([]*node, len(n.children))
+	for i, child := range n.children {
+		c.children[i] = child.clone()
+	}
+	return &c
 }
 
-// Returns the handle registered with the given path (key). The values of
-// wildcards are saved to a map.
-// If no handle can be found, a TSR (trailing slash redirect) recommendation is
-// made if a handle exists with an extra (without the) trailing slash for the
-// given path.
-func (n *node) getValue(path string, params func() *Params) (handle Handle, ps *Params, tsr bool) {
+// setHandle replaces the handle and route for the method held by the leaf that
+// was registered with the given path, which must already exist.
+// Not concurrency-safe!
+func (n *node) setHandle(method, path string, handle Handle, route *Route) {
+	// The path of a route matches itself when treated as a request path,
+	// because each wildcard matches its own name.
+	handles, _, _ := n.getValue(method, path, nil)
+	for i := range handles {
+		if handles[i].method == method {
+			handles[i].handle = handle
+			handles[i].route = route
+			return
+		}
+	}
+	panic("no handle is registered for path '" + path + "'")
+}
+
+// Returns the table of handles registered with the given path (key), from
+// which the handle for the method can be selected. The values of wildcards
+// are saved to a map.
+// If no handle can be found for the method, a TSR (trailing slash redirect)
+// recommendation is made if a handle for the method exists with an extra
+// (without the) trailing slash for the given path. The table of handles is
+// returned even when it has no handle for the method, so that the other
+// allowed methods are available in a single traversal.
+func (n *node) getValue(method, path string, params func() *Params) (handles methodHandles, ps *Params, tsr bool) {
+	handles, _, ps, tsr = n.lookup(method, path, params)
+	return handles, ps, tsr
+}
+
+// lookup is getValue that also returns the handle that serves the method, as
+// selected by methodHandles.match, so that the table need not be searched
+// twice.
+func (n *node) lookup(method, path string, params func() *Params) (handles methodHandles, m *methodHandle, ps *Params, tsr bool) {
 walk: // Outer loop for walking the tree
 	for {
 		prefix := n.path
@@ -348,7 +629,7 @@ walk: // Outer loop for walking the tree
 					// Nothing found.
 					// We can recommend to redirect to the same URL without a
 					// trailing slash if a leaf exists for that path.
-					tsr = (path == "/" && n.handle != nil)
+					tsr = (path == "/" && n.handles.serves(method))
 					return
 				}
 
@@ -389,13 +670,16 @@ walk: // Outer loop for walking the tree
 						return
 					}
 
-					if handle = n.handle; handle != nil {
-						return
-					} else if len(n.children) == 1 {
+					if handles = n.handles; len(handles) > 0 {
+						if m = handles.match(method); m != nil {
+							return
+						}
+					}
+					if len(n.children) == 1 {
 						// No handle found. Check if a handle for this path + a
 						// trailing slash exists for TSR recommendation
 						n = n.children[0]
-						tsr = (n.path == "/" && n.handle != nil) || (n.path == "" && n.indices == "/")
+						tsr = (n.path == "/" && n.handles.serves(method)) || (n.path == "" && n.indices == "/")
 					}
 
 					return
@@ -415,7 +699,8 @@ walk: // Outer loop for walking the tree
 						}
 					}
 
-					handle = n.handle
+					handles = n.handles
+					m = handles.match(method)
 					return
 
 				default:
@@ -425,8 +710,10 @@ walk: // Outer loop for walking the tree
 		} else if path == prefix {
 			// We should have reached the node containing the handle.
 			// Check if this node has a handle registered.
-			if handle = n.handle; handle != nil {
-				return
+			if handles = n.handles; len(handles) > 0 {
+				if m = handles.match(method); m != nil {
+					return
+				}
 			}
 
 			// If there is no handle for this route, but this route has a
@@ -442,8 +729,8 @@ walk: // Outer loop for walking the tree
 			for i, c := range []byte(n.indices) {
 				if c == '/' {
 					n = n.children[i]
-					tsr = (len(n.path) == 1 && n.handle != nil) ||
-						(n.nType == catchAll && n.children[0].handle != nil)
+					tsr = (len(n.path) == 1 && n.handles.serves(method)) ||
+						(n.nType == catchAll && n.children[0].handles.serves(method))
 					return
 				}
 			}
@@ -454,36 +741,46 @@ walk: // Outer loop for walking the tree
 		// extra trailing slash if a leaf exists for that path
 		tsr = (path == "/") ||
 			(len(prefix) == len(path)+1 && prefix[len(path)] == '/' &&
-				path == prefix[:len(prefix)-1] && n.handle != nil)
+				path == prefix[:len(prefix)-1] && n.handles.serves(method))
 		return
 	}
 }
 
-// makePathList traverses the tree constructing a slice of all the paths leading
-// to each registered handler.
-func (n *node) makePathList(parents []*node, list []string) []string {
-	if n.handle != nil {
+// makePathList traverses the tree constructing, for each method, a slice of all
+// the paths leading to each registered handler. If method is blank, all methods
+// are included; otherwise only the paths for that method are included.
+func (n *node) makePathList(method string, parents []*node, list map[string][]string) {
+	if len(n.handles) > 0 {
 		buf := &strings.Builder{}
 		for _, p := range parents {
 			io.WriteString(buf, p.path)
 		}
 		io.WriteString(buf, n.path)
-		list = append(list, buf.String())
+		path := buf.String()
+
+		for _, mh := range n.handles {
+			if method == "" || method == mh.method {
+				if mh.keys != nil {
+					// its wildcards are named differently from the nodes'
+					list[mh.method] = append(list[mh.method], mh.path)
+				} else {
+					list[mh.method] = append(list[mh.method], path)
+				}
+			}
+		}
 	}
 
 	for _, c := range n.children {
-		list = c.makePathList(append(parents, n), list)
+		c.makePathList(method, append(parents, n), list)
 	}
-
-	sort.Strings(list)
-	return list
 }
 
-// Makes a case-insensitive lookup of the given path and tries to find a handler.
+// Makes a case-insensitive lookup of the given path and tries to find a handler
+// for the method.
 // It can optionally also fix trailing slashes.
 // It returns the case-corrected path and a bool indicating whether the lookup
 // was successful.
-func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
+func (n *node) findCaseInsensitivePath(method, path string, fixTrailingSlash bool) (fixedPath string, found bool) {
 	const stackBufSize = 128
 
 	// Use a static sized buffer on the stack in the common case.
@@ -494,6 +791,7 @@ func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixe
 	}
 
 	ciPath := n.findCaseInsensitivePathRec(
+		method,
 		path,
 		buf,       // Preallocate enough memory for new path
 		[4]byte{}, // Empty rune buffer
@@ -520,7 +818,7 @@ func shiftNRuneBytes(rb [4]byte, n int) [4]byte {
 }
 
 // Recursive case-insensitive lookup function used by n.findCaseInsensitivePath
-func (n *node) findCaseInsensitivePathRec(path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool) []byte {
+func (n *node) findCaseInsensitivePathRec(method, path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool) []byte {
 	npLen := len(n.path)
 
 walk: // Outer loop for walking the tree
@@ -580,7 +878,7 @@ walk: // Outer loop for walking the tree
 							// uppercase byte and the lowercase byte might exist
 							// as an index
 							if out := n.children[i].findCaseInsensitivePathRec(
-								path, ciPath, rb, fixTrailingSlash,
+								method, path, ciPath, rb, fixTrailingSlash,
 							); out != nil {
 								return out
 							}
@@ -609,7 +907,7 @@ walk: // Outer loop for walking the tree
 
 				// Nothing found. We can recommend to redirect to the same URL
 				// without a trailing slash if a leaf exists for that path
-				if fixTrailingSlash && path == "/" && n.handle != nil {
+				if fixTrailingSlash && path == "/" && n.handles.serves(method) {
 					return ciPath
 				}
 				return nil
@@ -644,20 +942,23 @@ walk: // Outer loop for walking the tree
 					return nil
 				}
 
-				if n.handle != nil {
+				if n.handles.serves(method) {
 					return ciPath
 				} else if fixTrailingSlash && len(n.children) == 1 {
 					// No handle found. Check if a handle for this path + a
 					// trailing slash exists
 					n = n.children[0]
-					if n.path == "/" && n.handle != nil {
+					if n.path == "/" && n.handles.serves(method) {
 						return append(ciPath, '/')
 					}
 				}
 				return nil
 
 			case catchAll:
-				return append(ciPath, path...)
+				if n.handles.serves(method) {
+					return append(ciPath, path...)
+				}
+				return nil
 
 			default:
 				panic("invalid node type")
@@ -665,7 +966,7 @@ walk: // Outer loop for walking the tree
 		} else {
 			// We should have reached the node containing the handle.
 			// Check if this node has a handle registered.
-			if n.handle != nil {
+			if n.handles.serves(method) {
 				return ciPath
 			}
 
@@ -675,8 +976,8 @@ walk: // Outer loop for walking the tree
 				for i, c := range []byte(n.indices) {
 					if c == '/' {
 						n = n.children[i]
-						if (len(n.path) == 1 && n.handle != nil) ||
-							(n.nType == catchAll && n.children[0].handle != nil) {
+						if (len(n.path) == 1 && n.handles.serves(method)) ||
+							(n.nType == catchAll && n.children[0].handles.serves(method)) {
 							return append(ciPath, '/')
 						}
 						return nil
@@ -694,7 +995,7 @@ walk: // Outer loop for walking the tree
 			return ciPath
 		}
 		if len(path)+1 == npLen && n.path[len(path)] == '/' &&
-			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.handle != nil {
+			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.handles.serves(method) {
 			return append(ciPath, n.path...)
 		}
 	}
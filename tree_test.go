@@ -15,7 +15,7 @@ import (
 )
 
 //func printChildren(n *node, prefix string) {
-//	fmt.Printf(" %02d:%02d %s%s [%d] %v %t %d\n", n.priority, n.maxParams, prefix, n.path, len(n.children), n.handle, n.wildChild, n.nType)
+//	fmt.Printf(" %02d:%02d %s%s [%d] %v %t %d\n", n.priority, n.maxParams, prefix, n.path, len(n.children), n.handles, n.wildChild, n.nType)
 //	for l := len(n.path); l > 0; l-- {
 //		prefix += " "
 //	}
@@ -47,7 +47,8 @@ func getParams() *Params {
 
 func checkRequests(t *testing.T, tree *node, requests testRequests) {
 	for _, request := range requests {
-		handler, psp, _ := tree.getValue(request.path, getParams)
+		handles, psp, _ := tree.getValue(http.MethodGet, request.path, getParams)
+		handler := handles.get(http.MethodGet)
 
 		switch {
 		case handler == nil:
@@ -80,9 +81,7 @@ func checkPriorities(g *GomegaWithT, n *node) uint32 {
 		prio += checkPriorities(g, n.children[i])
 	}
 
-	if n.handle != nil {
-		prio++
-	}
+	prio += uint32(len(n.handles))
 
 	g.Expect(n.priority).To(Equal(prio), n.path)
 
@@ -114,7 +113,7 @@ func TestTreeAddAndGet(t *testing.T) {
 		"/β",
 	}
 	for _, route := range routes {
-		tree.addRoute(route, fakeHandler(route))
+		tree.addRoute(http.MethodGet, route, fakeHandler(route))
 	}
 
 	// printChildren(tree, "")
@@ -158,7 +157,7 @@ func TestTreeWildcard(t *testing.T) {
 		"/info/:user/project/:project",
 	}
 	for _, route := range routes {
-		tree.addRoute(route, fakeHandler(route))
+		tree.addRoute(http.MethodGet, route, fakeHandler(route))
 	}
 
 	//printChildren(tree, "")
@@ -204,7 +203,7 @@ func testRoutes(t *testing.T, routes []testRoute) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route.path, nil)
+			tree.addRoute(http.MethodGet, route.path, nil)
 		})
 
 		if route.conflict {
@@ -219,6 +218,57 @@ func testRoutes(t *testing.T, routes []testRoute) {
 	// printChildren(tree, "")
 }
 
+func TestTreeMultipleMethods(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tree := &node{}
+
+	routes := [...]struct{ method, path string }{
+		{http.MethodGet, "/user/:name"},
+		{http.MethodPut, "/user/:name"},
+		{http.MethodGet, "/src/*filepath"},
+		{http.MethodPost, "/src/*filepath"},
+		{http.MethodPost, "/doc/"},
+	}
+	for _, route := range routes {
+		tree.addRoute(route.method, route.path, fakeHandler(route.method+" "+route.path))
+	}
+
+	handles, psp, _ := tree.getValue(http.MethodPut, "/user/gopher", getParams)
+	g.Expect(handles).To(HaveLen(2))
+	g.Expect(*psp).To(Equal(Params{Param{"name", "gopher"}}))
+	handles.get(http.MethodPut)(nil, nil, nil)
+	g.Expect(fakeHandlerValue).To(Equal("PUT /user/:name"))
+
+	handles, _, _ = tree.getValue(http.MethodPost, "/src/a/b", getParams)
+	handles.get(http.MethodPost)(nil, nil, nil)
+	g.Expect(fakeHandlerValue).To(Equal("POST /src/*filepath"))
+
+	// HEAD is served by GET, but is not registered as such
+	handles, _, _ = tree.getValue(http.MethodHead, "/user/gopher", nil)
+	g.Expect(handles.get(http.MethodHead)).To(BeNil())
+	g.Expect(handles.match(http.MethodHead)).NotTo(BeNil())
+
+	// the table is returned for other methods too, but no TSR is
+	// recommended for a path that has no handle for the method
+	handles, _, tsr := tree.getValue(http.MethodDelete, "/doc/", nil)
+	g.Expect(handles.has(http.MethodDelete)).To(BeFalse())
+	g.Expect(handles.has(http.MethodPost)).To(BeTrue())
+	g.Expect(tsr).To(BeFalse())
+
+	_, _, tsr = tree.getValue(http.MethodGet, "/doc", nil)
+	g.Expect(tsr).To(BeFalse())
+	_, _, tsr = tree.getValue(http.MethodPost, "/doc", nil)
+	g.Expect(tsr).To(BeTrue())
+
+	checkPriorities(g, tree)
+
+	recv := catchPanic(func() {
+		tree.addRoute(http.MethodPut, "/user/:name", fakeHandler("again"))
+	})
+	g.Expect(recv).NotTo(BeNil())
+}
+
 func TestTreeWildcardConflict(t *testing.T) {
 	routes := []testRoute{
 		{"/cmd/:tool/:sub", false},
@@ -269,7 +319,7 @@ func TestTreeDupliatePath(t *testing.T) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route, fakeHandler(route))
+			tree.addRoute(http.MethodGet, route, fakeHandler(route))
 		})
 		if recv != nil {
 			t.Fatalf("panic inserting route '%s': %v", route, recv)
@@ -277,7 +327,7 @@ func TestTreeDupliatePath(t *testing.T) {
 
 		// Add again
 		recv = catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(http.MethodGet, route, nil)
 		})
 		if recv == nil {
 			t.Fatalf("no panic while inserting duplicate route '%s", route)
@@ -307,7 +357,7 @@ func TestEmptyWildcardName(t *testing.T) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(http.MethodGet, route, nil)
 		})
 		if recv == nil {
 			t.Fatalf("no panic while inserting route with empty wildcard name '%s", route)
@@ -337,7 +387,7 @@ func TestTreeCatchAllConflictRoot(t *testing.T) {
 func TestTreeCatchMaxParams(t *testing.T) {
 	tree := &node{}
 	var route = "/cmd/*filepath"
-	tree.addRoute(route, fakeHandler(route))
+	tree.addRoute(http.MethodGet, route, fakeHandler(route))
 }
 
 func TestTreeDoubleWildcard(t *testing.T) {
@@ -353,7 +403,7 @@ func TestTreeDoubleWildcard(t *testing.T) {
 		route := routes[i]
 		tree := &node{}
 		recv := catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(http.MethodGet, route, nil)
 		})
 
 		if rs, ok := recv.(string); !ok || !strings.HasPrefix(rs, panicMsg) {
@@ -395,7 +445,7 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route, fakeHandler(route))
+			tree.addRoute(http.MethodGet, route, fakeHandler(route))
 		})
 		if recv != nil {
 			t.Fatalf("panic inserting route '%s': %v", route, recv)
@@ -422,8 +472,8 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 		"/vendor/x",
 	}
 	for _, route := range tsrRoutes {
-		handler, _, tsr := tree.getValue(route, nil)
-		if handler != nil {
+		handles, _, tsr := tree.getValue(http.MethodGet, route, nil)
+		if handles.has(http.MethodGet) {
 			t.Fatalf("non-nil handler for TSR route '%s", route)
 		} else if !tsr {
 			t.Errorf("expected TSR recommendation for route '%s'", route)
@@ -439,8 +489,8 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 		"/api/world/abc",
 	}
 	for _, route := range noTsrRoutes {
-		handler, _, tsr := tree.getValue(route, nil)
-		if handler != nil {
+		handles, _, tsr := tree.getValue(http.MethodGet, route, nil)
+		if handles.has(http.MethodGet) {
 			t.Fatalf("non-nil handler for No-TSR route '%s", route)
 		} else if tsr {
 			t.Errorf("expected no TSR recommendation for route '%s'", route)
@@ -452,14 +502,14 @@ func TestTreeRootTrailingSlashRedirect(t *testing.T) {
 	tree := &node{}
 
 	recv := catchPanic(func() {
-		tree.addRoute("/:test", fakeHandler("/:test"))
+		tree.addRoute(http.MethodGet, "/:test", fakeHandler("/:test"))
 	})
 	if recv != nil {
 		t.Fatalf("panic inserting test route: %v", recv)
 	}
 
-	handler, _, tsr := tree.getValue("/", nil)
-	if handler != nil {
+	handles, _, tsr := tree.getValue(http.MethodGet, "/", nil)
+	if handles.has(http.MethodGet) {
 		t.Fatalf("non-nil handler")
 	} else if tsr {
 		t.Errorf("expected no TSR recommendation")
@@ -511,7 +561,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route, fakeHandler(route))
+			tree.addRoute(http.MethodGet, route, fakeHandler(route))
 		})
 		if recv != nil {
 			t.Fatalf("panic inserting route '%s': %v", route, recv)
@@ -522,7 +572,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	// With fixTrailingSlash = true
 	for i := range routes {
 		route := routes[i]
-		out, found := tree.findCaseInsensitivePath(route, true)
+		out, found := tree.findCaseInsensitivePath(http.MethodGet, route, true)
 		if !found {
 			t.Errorf("Route '%s' not found.", route)
 		} else if out != route {
@@ -532,7 +582,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	// With fixTrailingSlash = false
 	for i := range routes {
 		route := routes[i]
-		out, found := tree.findCaseInsensitivePath(route, false)
+		out, found := tree.findCaseInsensitivePath(http.MethodGet, route, false)
 		if !found {
 			t.Errorf("Route '%s' not found.", route)
 		} else if out != route {
@@ -605,7 +655,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	}
 	// With fixTrailingSlash = true
 	for _, test := range tests {
-		out, found := tree.findCaseInsensitivePath(test.in, true)
+		out, found := tree.findCaseInsensitivePath(http.MethodGet, test.in, true)
 		if found != test.found || (found && (out != test.out)) {
 			t.Errorf("Wrong result for '%s': got %s, %t; want %s, %t",
 				test.in, out, found, test.out, test.found)
@@ -614,7 +664,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	}
 	// With fixTrailingSlash = false
 	for _, test := range tests {
-		out, found := tree.findCaseInsensitivePath(test.in, false)
+		out, found := tree.findCaseInsensitivePath(http.MethodGet, test.in, false)
 		if test.slash {
 			if found { // test needs a trailingSlash fix. It must not be found!
 				t.Errorf("Found without fixTrailingSlash: %s; got %s", test.in, out)
@@ -629,19 +679,35 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 	}
 }
 
+func TestTreeFindCaseInsensitivePathOtherMethod(t *testing.T) {
+	tree := &node{}
+	tree.addRoute(http.MethodGet, "/src/*filepath", fakeHandler("/src/*filepath"))
+	tree.addRoute(http.MethodGet, "/user/:name", fakeHandler("/user/:name"))
+
+	for _, path := range []string{"/src/a.txt", "/SRC/a.txt", "/user/x", "/USER/x"} {
+		if out, found := tree.findCaseInsensitivePath(http.MethodPost, path, true); found {
+			t.Errorf("Found '%s' for another method: got %s", path, out)
+		}
+	}
+
+	if out, found := tree.findCaseInsensitivePath(http.MethodGet, "/SRC/a.txt", true); !found || out != "/src/a.txt" {
+		t.Errorf("Wrong result for '/SRC/a.txt': got %s, %t", out, found)
+	}
+}
+
 func TestTreeInvalidNodeType(t *testing.T) {
 	const panicMsg = "invalid node type"
 
 	tree := &node{}
-	tree.addRoute("/", fakeHandler("/"))
-	tree.addRoute("/:page", fakeHandler("/:page"))
+	tree.addRoute(http.MethodGet, "/", fakeHandler("/"))
+	tree.addRoute(http.MethodGet, "/:page", fakeHandler("/:page"))
 
 	// set invalid node type
 	tree.children[0].nType = 42
 
 	// normal lookup
 	recv := catchPanic(func() {
-		tree.getValue("/test", nil)
+		tree.getValue(http.MethodGet, "/test", nil)
 	})
 	if rs, ok := recv.(string); !ok || rs != panicMsg {
 		t.Fatalf("Expected panic '"+panicMsg+"', got '%v'", recv)
@@ -649,7 +715,7 @@ func TestTreeInvalidNodeType(t *testing.T) {
 
 	// case-insensitive lookup
 	recv = catchPanic(func() {
-		tree.findCaseInsensitivePath("/test", true)
+		tree.findCaseInsensitivePath(http.MethodGet, "/test", true)
 	})
 	if rs, ok := recv.(string); !ok || rs != panicMsg {
 		t.Fatalf("Expected panic '"+panicMsg+"', got '%v'", recv)
@@ -685,11 +751,11 @@ func TestTreeWildcardConflictEx(t *testing.T) {
 
 		for i := range routes {
 			route := routes[i]
-			tree.addRoute(route, fakeHandler(route))
+			tree.addRoute(http.MethodGet, route, fakeHandler(route))
 		}
 
 		recv := catchPanic(func() {
-			tree.addRoute(conflict.route, fakeHandler(conflict.route))
+			tree.addRoute(http.MethodGet, conflict.route, fakeHandler(conflict.route))
 		})
 
 		if !regexp.MustCompile(fmt.Sprintf("'%s' in new path .* conflicts with existing wildcard '%s' in existing prefix '%s'", conflict.segPath, conflict.existSegPath, conflict.existPath)).MatchString(fmt.Sprint(recv)) {
@@ -55,3 +55,12 @@ func doStripLeadingSegments(r *http.Request, unwantedSegments uint) *http.Reques
 	r2.URL.RawPath = "" // throw away original (which is usually blank anyway)
 	return r2
 }
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
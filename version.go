@@ -0,0 +1,303 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// VersionedAPI registers routes under several API versions, such as "v1" and
+// "v2". It is created by Router.Versions.
+//
+// By default, the version is selected by a path prefix, so a route "/users/:id"
+// registered for version "v2" handles requests for "/v2/users/:id". Otherwise,
+// the version can be selected by a request header (see ByHeader).
+type VersionedAPI struct {
+	router         *Router
+	versions       []string
+	header         string
+	defaultVersion string
+	fallback       bool
+	slots          map[versionKey]*versionSlot
+	byHeader       map[routeKey]*Route // the routes registered when using ByHeader
+}
+
+// versionKey identifies the route for a method and path in one version.
+type versionKey struct {
+	method, path, version string
+}
+
+// versionSlot holds the route that serves a method and path in one version.
+// The route is either registered explicitly for this version or is inherited
+// from an earlier version.
+type versionSlot struct {
+	route    *VersionedRoute
+	rt       *Route // the route registered with the router
+	from     int    // the index of the version for which the route was registered
+	explicit bool
+}
+
+// VersionedRoute is a route registered via a VersionedAPI.
+type VersionedRoute struct {
+	handle      Handle
+	deprecation string
+	sunset      string
+	links       []string
+	routes      []*Route
+}
+
+// Versions creates a VersionedAPI for the given versions, which must be listed
+// from the oldest to the newest.
+func (r *Router) Versions(versions ...string) *VersionedAPI {
+	if len(versions) == 0 {
+		panic("at least one version is required")
+	}
+	for i, v := range versions {
+		if v == "" {
+			panic("versions must not be empty")
+		}
+		if contains(versions[:i], v) {
+			panic("duplicate version '" + v + "'")
+		}
+	}
+
+	return &VersionedAPI{
+		router:         r,
+		versions:       versions,
+		defaultVersion: versions[len(versions)-1],
+		slots:          make(map[versionKey]*versionSlot),
+		byHeader:       make(map[routeKey]*Route),
+	}
+}
+
+// ByHeader selects the version using the named request header instead of a
+// path prefix. Requests without the header are served by the default version,
+// which is the newest unless altered by DefaultVersion. Requests for unknown
+// versions receive 404 Not Found.
+//
+// This must be set before any routes are registered.
+func (v *VersionedAPI) ByHeader(name string) *VersionedAPI {
+	v.mustBeEmpty("ByHeader")
+	v.header = http.CanonicalHeaderKey(name)
+	return v
+}
+
+// DefaultVersion sets the version used for requests without a version header.
+// This only applies when ByHeader is in use.
+func (v *VersionedAPI) DefaultVersion(version string) *VersionedAPI {
+	if !contains(v.versions, version) {
+		panic("unknown version '" + version + "'")
+	}
+	v.defaultVersion = version
+	return v
+}
+
+// WithFallback allows each route to serve later versions too, unless they
+// have their own route for the same method and path. For example, if a route
+// is registered only for "v1", it will also serve "v2" requests.
+//
+// This must be set before any routes are registered.
+func (v *VersionedAPI) WithFallback() *VersionedAPI {
+	v.mustBeEmpty("WithFallback")
+	v.fallback = true
+	return v
+}
+
+func (v *VersionedAPI) mustBeEmpty(option string) {
+	if len(v.slots) > 0 {
+		panic(option + " must be set before any routes are registered")
+	}
+}
+
+// Handle registers a new request handle with the given method and path, for
+// the listed versions. If no versions are listed, the route is registered for
+// all versions.
+func (v *VersionedAPI) Handle(method, path string, handle Handle, versions ...string) *VersionedRoute {
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+
+	if len(versions) == 0 {
+		versions = v.versions
+	}
+
+	vr := &VersionedRoute{handle: handle}
+
+	for _, version := range versions {
+		i := v.indexOf(version)
+		if i < 0 {
+			panic("unknown version '" + version + "' in path '" + path + "'")
+		}
+
+		slot := v.slot(method, path, version)
+		if slot.explicit {
+			panic("a handle is already registered for version '" + version + "' of path '" + path + "'")
+		}
+		*slot = versionSlot{route: vr, rt: slot.rt, from: i, explicit: true}
+		if !containsRoute(vr.routes, slot.rt) {
+			vr.routes = append(vr.routes, slot.rt)
+		}
+
+		if v.fallback {
+			for j := i + 1; j < len(v.versions); j++ {
+				later := v.slot(method, path, v.versions[j])
+				if later.route == nil || (!later.explicit && later.from < i) {
+					*later = versionSlot{route: vr, rt: later.rt, from: i}
+				}
+			}
+		}
+	}
+
+	return vr
+}
+
+// GET is a shortcut for v.Handle(http.MethodGet, path, handle, versions...)
+func (v *VersionedAPI) GET(path string, handle Handle, versions ...string) *VersionedRoute {
+	return v.Handle(http.MethodGet, path, handle, versions...)
+}
+
+// POST is a shortcut for v.Handle(http.MethodPost, path, handle, versions...)
+func (v *VersionedAPI) POST(path string, handle Handle, versions ...string) *VersionedRoute {
+	return v.Handle(http.MethodPost, path, handle, versions...)
+}
+
+// PUT is a shortcut for v.Handle(http.MethodPut, path, handle, versions...)
+func (v *VersionedAPI) PUT(path string, handle Handle, versions ...string) *VersionedRoute {
+	return v.Handle(http.MethodPut, path, handle, versions...)
+}
+
+// PATCH is a shortcut for v.Handle(http.MethodPatch, path, handle, versions...)
+func (v *VersionedAPI) PATCH(path string, handle Handle, versions ...string) *VersionedRoute {
+	return v.Handle(http.MethodPatch, path, handle, versions...)
+}
+
+// DELETE is a shortcut for v.Handle(http.MethodDelete, path, handle, versions...)
+func (v *VersionedAPI) DELETE(path string, handle Handle, versions ...string) *VersionedRoute {
+	return v.Handle(http.MethodDelete, path, handle, versions...)
+}
+
+func (v *VersionedAPI) indexOf(version string) int {
+	for i, s := range v.versions {
+		if s == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// slot gets the slot for a method, path and version, registering the
+// corresponding route with the router the first time.
+func (v *VersionedAPI) slot(method, path, version string) *versionSlot {
+	key := versionKey{method: method, path: path, version: version}
+	if slot, exists := v.slots[key]; exists {
+		return slot
+	}
+
+	slot := &versionSlot{}
+	v.slots[key] = slot
+
+	if v.header == "" {
+		slot.rt = v.router.Handle(method, "/"+version+path, func(w http.ResponseWriter, req *http.Request, ps Params) {
+			slot.serve(w, req, ps)
+		})
+	} else {
+		key := routeKey{method: method, path: path}
+		if v.byHeader[key] == nil {
+			v.byHeader[key] = v.router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps Params) {
+				v.serveByHeader(method, path, w, req, ps)
+			})
+		}
+		slot.rt = v.byHeader[key]
+	}
+
+	return slot
+}
+
+func (v *VersionedAPI) serveByHeader(method, path string, w http.ResponseWriter, req *http.Request, ps Params) {
+	w.Header().Add("Vary", v.header)
+
+	version := req.Header.Get(v.header)
+	if version == "" {
+		version = v.defaultVersion
+	}
+
+	slot := v.slots[versionKey{method: method, path: path, version: version}]
+	if slot == nil || slot.route == nil {
+		// the route's router is used, because it changes if the router is
+		// mounted (see Router.Mount)
+		rt := v.byHeader[routeKey{method: method, path: path}]
+		// the request has already been observed, so this only alters the outcome
+		rt.router.observe(w, req, rt.path, NotFound)
+		rt.router.notFound(w, req)
+		return
+	}
+
+	slot.serve(w, req, ps)
+}
+
+// Routes gets the routes registered with the router for the versions listed
+// when this route was registered. These can be refined, e.g. with Produces or
+// Timeout. With a path prefix, there is one route per version. With ByHeader,
+// each route is shared by all versions of the same method and path, so it
+// serves every version.
+//
+// Requests for later versions that are served by this route via WithFallback
+// use the routes of those versions.
+func (vr *VersionedRoute) Routes() []*Route {
+	return vr.routes
+}
+
+// Deprecated marks the route as deprecated, so that its responses for the
+// versions it was registered for include the Deprecation header (RFC 9745)
+// and, if sunset is not zero, the Sunset header (RFC 8594). The deprecation
+// time may be zero if it is not known. If link is not blank, it is sent in a
+// Link header with the "deprecation" relation type; this should refer to
+// documentation about the deprecation. Responses for later versions served
+// via WithFallback do not include these headers.
+func (vr *VersionedRoute) Deprecated(deprecation, sunset time.Time, link string) *VersionedRoute {
+	vr.deprecation = "true"
+	if !deprecation.IsZero() {
+		vr.deprecation = "@" + strconv.FormatInt(deprecation.Unix(), 10)
+	}
+
+	if !sunset.IsZero() {
+		vr.sunset = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	if link != "" {
+		vr.links = append(vr.links, "<"+link+`>; rel="deprecation"`)
+	}
+	return vr
+}
+
+// serve calls the slot's route. The deprecation headers are only sent for the
+// versions the route was registered for, not for later versions it serves via
+// the fallback.
+func (slot *versionSlot) serve(w http.ResponseWriter, req *http.Request, ps Params) {
+	vr := slot.route
+	if vr.deprecation != "" && slot.explicit {
+		h := w.Header()
+		h.Set("Deprecation", vr.deprecation)
+		if vr.sunset != "" {
+			h.Set("Sunset", vr.sunset)
+		}
+		for _, link := range vr.links {
+			h.Add("Link", link)
+		}
+	}
+
+	vr.handle(w, req, ps)
+}
+
+func containsRoute(routes []*Route, rt *Route) bool {
+	for _, r := range routes {
+		if r == rt {
+			return true
+		}
+	}
+	return false
+}
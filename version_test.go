@@ -0,0 +1,225 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func versionedHandle(saw *string, name string) Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps Params) {
+		*saw = name + " " + ps.ByName("id")
+	}
+}
+
+func TestVersionedAPI_by_path(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	api := router.Versions("v1", "v2", "v3").WithFallback()
+	api.GET("/users/:id", versionedHandle(&saw, "users-v1"), "v1")
+	api.GET("/users/:id", versionedHandle(&saw, "users-v3"), "v3")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v2"), "v2")
+	api.GET("/all/:id", versionedHandle(&saw, "all"))
+
+	cases := []struct {
+		url, expected string
+		code          int
+	}{
+		{"/v1/users/1", "users-v1 1", http.StatusOK},
+		{"/v2/users/2", "users-v1 2", http.StatusOK}, // fallback
+		{"/v3/users/3", "users-v3 3", http.StatusOK},
+		{"/v1/orders/1", "", http.StatusNotFound},
+		{"/v2/orders/2", "orders-v2 2", http.StatusOK},
+		{"/v3/orders/3", "orders-v2 3", http.StatusOK}, // fallback
+		{"/v1/all/1", "all 1", http.StatusOK},
+		{"/v3/all/3", "all 3", http.StatusOK},
+		{"/v4/all/4", "", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		saw = ""
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.url, nil))
+		g.Expect(w.Code).To(Equal(c.code), c.url)
+		g.Expect(saw).To(Equal(c.expected), c.url)
+	}
+
+	recv := catchPanic(func() {
+		api.GET("/users/:id", versionedHandle(&saw, "again"), "v1")
+	})
+	g.Expect(recv).NotTo(BeNil())
+
+	recv = catchPanic(func() {
+		api.GET("/users/:id", versionedHandle(&saw, "again"), "v9")
+	})
+	g.Expect(recv).NotTo(BeNil())
+}
+
+func TestVersionedAPI_without_fallback(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	api := router.Versions("v1", "v2")
+	api.GET("/users/:id", versionedHandle(&saw, "users-v1"), "v1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/users/1", nil))
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+}
+
+func TestVersionedAPI_by_header(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	api := router.Versions("1", "2").ByHeader("accept-version").WithFallback().DefaultVersion("1")
+	api.GET("/users/:id", versionedHandle(&saw, "users-v1"), "1")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v1"), "1")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v2"), "2")
+
+	cases := []struct {
+		url, version, expected string
+		code                   int
+	}{
+		{"/users/1", "", "users-v1 1", http.StatusOK},
+		{"/users/1", "2", "users-v1 1", http.StatusOK},
+		{"/orders/1", "", "orders-v1 1", http.StatusOK},
+		{"/orders/1", "2", "orders-v2 1", http.StatusOK},
+		{"/orders/1", "3", "", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		saw = ""
+		r := httptest.NewRequest(http.MethodGet, c.url, nil)
+		if c.version != "" {
+			r.Header.Set("Accept-Version", c.version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		g.Expect(w.Code).To(Equal(c.code), c.url+" "+c.version)
+		g.Expect(saw).To(Equal(c.expected), c.url+" "+c.version)
+		g.Expect(w.Header().Get("Vary")).To(Equal("Accept-Version"))
+	}
+}
+
+func TestVersionedAPI_by_header_unknown_version(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	observer := &recordingObserver{}
+	router := New()
+	router.Observer = observer
+	router.Fallback(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		saw = "fallback"
+	}))
+	api := router.Versions("1", "2").ByHeader("Accept-Version")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v1"), "1")
+
+	r := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	r.Header.Set("Accept-Version", "3")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+	g.Expect(saw).To(BeEmpty())
+	g.Expect(observer.started).To(HaveLen(1))
+	g.Expect(observer.ended).To(HaveLen(1))
+	g.Expect(observer.ended[0].Pattern).To(Equal("/orders/:id"))
+	g.Expect(observer.ended[0].Outcome).To(Equal(NotFound))
+	g.Expect(observer.ended[0].Status).To(Equal(http.StatusNotFound))
+}
+
+func TestVersionedAPI_Deprecated(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	deprecation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	router := New()
+	api := router.Versions("v1", "v2").WithFallback()
+	api.GET("/users/:id", versionedHandle(&saw, "users-v1"), "v1").
+		Deprecated(deprecation, sunset, "https://example.com/deprecation")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v1"), "v1")
+	api.GET("/orders/:id", versionedHandle(&saw, "orders-v2"), "v2")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/1", nil))
+	g.Expect(w.Header().Get("Deprecation")).To(Equal("@1767225600"))
+	g.Expect(w.Header().Get("Sunset")).To(Equal("Thu, 31 Dec 2026 23:59:59 GMT"))
+	g.Expect(w.Header().Get("Link")).To(Equal(`<https://example.com/deprecation>; rel="deprecation"`))
+
+	// v2 is served by the v1 route via the fallback, but is not deprecated
+	saw = ""
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/users/1", nil))
+	g.Expect(saw).To(Equal("users-v1 1"))
+	g.Expect(w.Header().Get("Deprecation")).To(Equal(""))
+	g.Expect(w.Header().Get("Sunset")).To(Equal(""))
+	g.Expect(w.Header().Get("Link")).To(Equal(""))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/orders/1", nil))
+	g.Expect(w.Header().Get("Deprecation")).To(Equal(""))
+	g.Expect(w.Header().Get("Sunset")).To(Equal(""))
+}
+
+func TestVersionedAPI_Routes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	api := router.Versions("v1", "v2", "v3")
+	vr := api.GET("/users/:id", versionedHandle(&saw, "users"), "v1", "v3")
+
+	g.Expect(vr.Routes()).To(HaveLen(2))
+	g.Expect(vr.Routes()[0].Path()).To(Equal("/v1/users/:id"))
+	g.Expect(vr.Routes()[1].Path()).To(Equal("/v3/users/:id"))
+
+	vr.Routes()[1].Produces("application/json")
+
+	r := httptest.NewRequest(http.MethodGet, "/v3/users/1", nil)
+	r.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusNotAcceptable))
+
+	r = httptest.NewRequest(http.MethodGet, "/v1/users/1", nil)
+	r.Header.Set("Accept", "text/csv")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(saw).To(Equal("users 1"))
+}
+
+func TestVersionedAPI_Routes_by_header(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	router := New()
+	api := router.Versions("1", "2").ByHeader("Accept-Version")
+	vr := api.GET("/users/:id", versionedHandle(&saw, "users"))
+
+	g.Expect(vr.Routes()).To(HaveLen(1))
+	g.Expect(vr.Routes()[0].Path()).To(Equal("/users/:id"))
+}
+
+func TestVersionedAPI_options_after_routes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var saw string
+	api := New().Versions("v1", "v2")
+	api.GET("/users/:id", versionedHandle(&saw, "users"))
+
+	g.Expect(func() { api.ByHeader("Accept-Version") }).To(Panic())
+	g.Expect(func() { api.WithFallback() }).To(Panic())
+}
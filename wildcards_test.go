@@ -0,0 +1,157 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// echoRoute writes the method and pattern of the route and the parameters.
+func echoRoute(pattern string) Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps Params) {
+		w.Write([]byte(req.Method + " " + pattern))
+		for _, p := range ps {
+			w.Write([]byte(" " + p.Key + "=" + p.Value))
+		}
+	}
+}
+
+func TestRouter_wildcardNamesPerMethod(t *testing.T) {
+	cases := []struct {
+		first, second routeKey
+		requests      map[string]string // "METHOD path" -> expected body
+	}{
+		{
+			first:  routeKey{method: http.MethodGet, path: "/u/:id"},
+			second: routeKey{method: http.MethodPost, path: "/u/:name"},
+			requests: map[string]string{
+				"GET /u/1":     "GET /u/:id id=1",
+				"POST /u/bob":  "POST /u/:name name=bob",
+				"HEAD /u/1":    "HEAD /u/:id id=1",
+				"DELETE /u/1":  "Method Not Allowed\n",
+				"GET /missing": "404 page not found\n",
+			},
+		},
+		{
+			first:  routeKey{method: http.MethodGet, path: "/v/:id/items/:item"},
+			second: routeKey{method: http.MethodPut, path: "/v/:vid/items/:n"},
+			requests: map[string]string{
+				"GET /v/1/items/2": "GET /v/:id/items/:item id=1 item=2",
+				"PUT /v/1/items/2": "PUT /v/:vid/items/:n vid=1 n=2",
+			},
+		},
+		{
+			first:  routeKey{method: http.MethodGet, path: "/src/*filepath"},
+			second: routeKey{method: http.MethodPost, path: "/src/*upload"},
+			requests: map[string]string{
+				"GET /src/a/b.go":  "GET /src/*filepath filepath=/a/b.go",
+				"POST /src/a/b.go": "POST /src/*upload upload=/a/b.go",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		for _, order := range [][]routeKey{{c.first, c.second}, {c.second, c.first}} {
+			g := NewGomegaWithT(t)
+
+			router := New()
+			for _, key := range order {
+				router.Handle(key.method, key.path, echoRoute(key.path))
+			}
+
+			for request, body := range c.requests {
+				method, path, _ := strings.Cut(request, " ")
+				w := serveStatic(router, method, path)
+				g.Expect(w.Body.String()).To(Equal(body), request)
+			}
+
+			g.Expect(router.ListPaths("")).To(Equal(map[string][]string{
+				c.first.method:  {c.first.path},
+				c.second.method: {c.second.path},
+			}))
+
+			handle, ps, _ := router.Lookup(c.second.method, c.second.path)
+			g.Expect(handle).NotTo(BeNil())
+			g.Expect(ps[0].Key).To(Equal(wildcardKeys(c.second.path)[0]))
+		}
+	}
+}
+
+func TestRouter_wildcardNamesPerMethod_allowed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop)
+	router.POST("/users/:name", noop)
+	router.PUT("/users/:user", noop)
+
+	w := serveStatic(router, http.MethodDelete, "/users/7")
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(w.Header().Get("Allow")).To(Equal("GET, OPTIONS, POST, PUT"))
+
+	w = serveStatic(router, http.MethodOptions, "/users/7")
+	g.Expect(w.Header().Get("Allow")).To(Equal("GET, OPTIONS, POST, PUT"))
+}
+
+func TestRouter_wildcardNamesPerMethod_conflicts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop)
+	router.POST("/users/:name", noop)
+
+	// each method's routes must still agree on the name
+	g.Expect(func() { router.POST("/users/:id/edit", noop) }).To(PanicWith(BeAssignableToTypeOf(&ConflictError{})))
+	g.Expect(func() { router.GET("/users/:name/edit", noop) }).To(PanicWith(BeAssignableToTypeOf(&ConflictError{})))
+
+	// and static segments still conflict with wildcards across methods
+	g.Expect(func() { router.PUT("/users/new", noop) }).To(PanicWith(BeAssignableToTypeOf(&ConflictError{})))
+
+	router.POST("/users/:name/edit", noop)
+	router.GET("/users/:id/edit", noop)
+
+	g.Expect(router.ListPaths("")).To(Equal(map[string][]string{
+		http.MethodGet:  {"/users/:id", "/users/:id/edit"},
+		http.MethodPost: {"/users/:name", "/users/:name/edit"},
+	}))
+
+	w := serveStatic(router, http.MethodPost, "/users/bob/edit")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+}
+
+func TestRouter_wildcardNamesPerMethod_conflictNamesMethodWildcard(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/a/:x", noop)
+	router.POST("/a/:q", noop)
+
+	_, err := router.TryHandle(http.MethodPost, "/a/:z/d", noop)
+
+	var conflict *ConflictError
+	g.Expect(errors.As(err, &conflict)).To(BeTrue())
+	g.Expect(conflict.Existing).To(Equal("/a/:q"))
+	g.Expect(conflict.Segment).To(Equal(":z"))
+	g.Expect(err.Error()).To(Equal("':z' in new path '/a/:z/d' conflicts with existing wildcard ':q' in existing prefix '/a/:q'"))
+}
+
+func TestRouter_wildcardNamesPerMethod_validate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := New()
+	router.GET("/users/:id", noop)
+
+	errs := router.Validate(
+		RouteSpec{Method: http.MethodPost, Path: "/users/:name"},
+		RouteSpec{Method: http.MethodPost, Path: "/users/:id/edit"},
+	)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].(*ConflictError).Path).To(Equal("/users/:id/edit"))
+}
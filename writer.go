@@ -0,0 +1,145 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// StatusWriter wraps a http.ResponseWriter, capturing the status code and the
+// number of bytes written.
+//
+// It always implements http.Flusher, http.Hijacker, http.Pusher and
+// io.ReaderFrom, whether or not the wrapped writer does, so a type assertion
+// does not reveal what the wrapped writer supports. As with
+// http.ResponseController, Hijack and Push return http.ErrNotSupported and
+// Flush does nothing if the wrapped writer, or any writer that it unwraps to,
+// lacks the capability. So use http.ResponseController, which also uses
+// FlushError to report whether flushing is supported.
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+var (
+	_ http.Flusher  = &StatusWriter{}
+	_ http.Hijacker = &StatusWriter{}
+	_ http.Pusher   = &StatusWriter{}
+	_ io.ReaderFrom = &StatusWriter{}
+)
+
+// NewStatusWriter wraps a http.ResponseWriter. If it is already a
+// StatusWriter, it is returned unchanged.
+func NewStatusWriter(w http.ResponseWriter) *StatusWriter {
+	if sw, ok := w.(*StatusWriter); ok {
+		return sw
+	}
+	return &StatusWriter{ResponseWriter: w}
+}
+
+// Status gets the status code written, or zero if the header has not yet
+// been written.
+func (w *StatusWriter) Status() int {
+	return w.status
+}
+
+// Size gets the number of bytes of the response body written so far.
+func (w *StatusWriter) Size() int64 {
+	return w.size
+}
+
+// Committed is true when the response header has been written.
+func (w *StatusWriter) Committed() bool {
+	return w.status != 0
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *StatusWriter) WriteHeader(code int) {
+	if w.status == 0 || (w.status >= 100 && w.status <= 199 && code >= 200) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *StatusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// ReadFrom implements io.ReaderFrom, using the wrapped writer's ReadFrom if it
+// has one, so that optimisations such as sendfile are not lost.
+func (w *StatusWriter) ReadFrom(src io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(writerOnly{w.ResponseWriter}, src)
+	}
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher. It does nothing if the wrapped writer cannot
+// be flushed.
+func (w *StatusWriter) Flush() {
+	w.FlushError()
+}
+
+// FlushError flushes the wrapped writer, as http.ResponseController does. It
+// returns an error wrapping http.ErrNotSupported if the wrapped writer cannot
+// be flushed.
+func (w *StatusWriter) FlushError() error {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker. It returns http.ErrNotSupported if the
+// wrapped writer cannot be hijacked.
+func (w *StatusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil, nil, http.ErrNotSupported
+	}
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Push implements http.Pusher. It returns http.ErrNotSupported if the wrapped
+// writer is not a http.Pusher.
+func (w *StatusWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writerOnly hides any ReadFrom method, preventing io.Copy from recursing.
+type writerOnly struct {
+	io.Writer
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"bufio"
+	. "github.com/onsi/gomega"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+	readFrom bool
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+func (h *hijackableRecorder) ReadFrom(src io.Reader) (int64, error) {
+	h.readFrom = true
+	return io.Copy(h.ResponseRecorder, src)
+}
+
+func TestStatusWriter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rec := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw := NewStatusWriter(rec)
+	g.Expect(NewStatusWriter(sw)).To(BeIdenticalTo(sw))
+	g.Expect(sw.Committed()).To(BeFalse())
+
+	sw.WriteHeader(http.StatusAccepted)
+	sw.Write([]byte("abc"))
+	n, err := sw.ReadFrom(strings.NewReader("defg"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(Equal(int64(4)))
+	sw.Flush()
+
+	g.Expect(sw.Status()).To(Equal(http.StatusAccepted))
+	g.Expect(sw.Size()).To(Equal(int64(7)))
+	g.Expect(sw.Committed()).To(BeTrue())
+	g.Expect(rec.Body.String()).To(Equal("abcdefg"))
+	g.Expect(rec.Flushed).To(BeTrue())
+	g.Expect(rec.readFrom).To(BeTrue())
+
+	_, _, err = sw.Hijack()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rec.hijacked).To(BeTrue())
+	g.Expect(http.NewResponseController(sw).Flush()).To(Succeed())
+	g.Expect(sw.Push("/app.css", nil)).To(MatchError(http.ErrNotSupported))
+}
+
+func TestStatusWriter_without_optional_interfaces(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rec := httptest.NewRecorder()
+	sw := NewStatusWriter(struct{ http.ResponseWriter }{rec})
+
+	n, err := sw.ReadFrom(strings.NewReader("hello"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(Equal(int64(5)))
+	g.Expect(sw.Status()).To(Equal(http.StatusOK))
+
+	_, _, err = sw.Hijack()
+	g.Expect(err).To(Equal(http.ErrNotSupported))
+	g.Expect(http.NewResponseController(sw).Flush()).To(MatchError(http.ErrNotSupported))
+	g.Expect(rec.Flushed).To(BeFalse())
+}
+
+// unwrapper hides the capabilities of the writer, except via Unwrap.
+type unwrapper struct {
+	http.ResponseWriter
+}
+
+func (u unwrapper) Unwrap() http.ResponseWriter {
+	return u.ResponseWriter
+}
+
+func TestStatusWriter_unwraps(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rec := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw := NewStatusWriter(unwrapper{rec})
+
+	g.Expect(http.NewResponseController(sw).Flush()).To(Succeed())
+	g.Expect(rec.Flushed).To(BeTrue())
+
+	_, _, err := sw.Hijack()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rec.hijacked).To(BeTrue())
+}